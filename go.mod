@@ -8,6 +8,8 @@ require (
 	github.com/gin-gonic/gin v1.6.3
 	github.com/json-iterator/go v1.1.10 // indirect
 	github.com/liamylian/jsontime/v2 v2.0.0
+	github.com/mozillazg/go-pinyin v0.20.0
 	github.com/sirupsen/logrus v1.7.0
 	github.com/stretchr/testify v1.6.1
+	go.etcd.io/bbolt v1.3.5
 )
@@ -0,0 +1,180 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/internal/entities"
+	"stock/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AlertController struct {
+	service *services.AlertImpl
+}
+
+func NewAlertController(service *services.AlertImpl) *AlertController {
+	return &AlertController{
+		service: service,
+	}
+}
+
+type AlertRuleURI struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+type AlertRuleRequest struct {
+	Code      string             `json:"code" form:"code" binding:"required"`
+	Kind      entities.AlertKind `json:"kind" form:"kind" binding:"required"`
+	Threshold float64            `json:"threshold" form:"threshold"`
+	Cooldown  int64              `json:"cooldown" form:"cooldown" binding:"gte=0"`
+	Notifiers []string           `json:"notifiers" form:"notifiers[]"`
+	Enabled   *bool              `json:"enabled" form:"enabled"`
+}
+
+func (r *AlertRuleRequest) rule() *entities.AlertRule {
+	rule := &entities.AlertRule{
+		Code:      r.Code,
+		Kind:      r.Kind,
+		Threshold: r.Threshold,
+		Cooldown:  r.Cooldown,
+		Notifiers: r.Notifiers,
+		Enabled:   true,
+	}
+	if r.Enabled != nil {
+		rule.Enabled = *r.Enabled
+	}
+	return rule
+}
+
+func (c *AlertController) abort(ctx *gin.Context, err error, fields logrus.Fields) {
+	if errors.Is(err, services.ErrInvalidAlertKind) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	abortWithStoreError(ctx, err, fields)
+}
+
+func (c *AlertController) List(ctx *gin.Context) {
+	rules, err := c.service.AlertRules()
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": rules,
+	})
+}
+
+func (c *AlertController) Create(ctx *gin.Context) {
+	params := new(AlertRuleRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	rule := params.rule()
+	if err := c.service.CreateAlertRule(rule); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"code": params.Code,
+			"kind": params.Kind,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": rule,
+	})
+}
+
+func (c *AlertController) Update(ctx *gin.Context) {
+	uri := new(AlertRuleURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	params := new(AlertRuleRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	rule := params.rule()
+	rule.ID = uri.ID
+	if err := c.service.UpdateAlertRule(rule); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id":   uri.ID,
+			"code": params.Code,
+			"kind": params.Kind,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": rule,
+	})
+}
+
+func (c *AlertController) Delete(ctx *gin.Context) {
+	uri := new(AlertRuleURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err := c.service.DeleteAlertRule(uri.ID); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+	})
+}
+
+type AlertEventsRequest struct {
+	Limit int `json:"limit" form:"limit" binding:"gte=0,lte=500"`
+}
+
+func (c *AlertController) Events(ctx *gin.Context) {
+	params := new(AlertEventsRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.Limit == 0 {
+		params.Limit = 50
+	}
+	events, err := c.service.AlertEvents(params.Limit)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": events,
+	})
+}
@@ -0,0 +1,58 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/analytics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AnalyticsRequest struct {
+	Codes     []string  `json:"codes" form:"codes[]" binding:"required,min=1"`
+	Benchmark string    `json:"benchmark" form:"benchmark"`
+	StartTime time.Time `json:"start_time" form:"start_time" binding:"required" time_format:"2006-01-02 15:04:05"`
+	EndTime   time.Time `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+}
+
+func (c *Controller) Analytics(ctx *gin.Context) {
+	params := new(AnalyticsRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now()
+	}
+	result, err := c.service.Compare(params.Codes, params.Benchmark, params.StartTime, params.EndTime)
+	if errors.Is(err, analytics.ErrNotEnoughData) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"codes":      params.Codes,
+			"benchmark":  params.Benchmark,
+			"start_time": params.StartTime,
+			"end_time":   params.EndTime,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": result,
+	})
+}
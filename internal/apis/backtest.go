@@ -0,0 +1,87 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/backtest"
+	"stock/pkg/resample"
+	"stock/pkg/spiders"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type BacktestRequest struct {
+	Code      string       `json:"code" form:"code" binding:"required"`
+	Type      spiders.Type `json:"type" form:"type"`
+	StartTime time.Time    `json:"start_time" form:"start_time" binding:"required" time_format:"2006-01-02 15:04:05"`
+	EndTime   time.Time    `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+	Strategy  string       `json:"strategy" form:"strategy" binding:"required"`
+	Fast      int          `json:"fast" form:"fast"`
+	Slow      int          `json:"slow" form:"slow"`
+	Cash      float64      `json:"cash" form:"cash" binding:"gte=0"`
+}
+
+func (c *Controller) Backtest(ctx *gin.Context) {
+	params := new(BacktestRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.Type == "" {
+		params.Type = spiders.OneDay
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now()
+	}
+	if _, err := resample.ParsePeriod(string(params.Type)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	strategy, err := backtest.NewStrategy(params.Strategy, map[string]int{"fast": params.Fast, "slow": params.Slow})
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	cfg := backtest.DefaultConfig(params.Code)
+	if params.Cash > 0 {
+		cfg.Cash = params.Cash
+	}
+	report, err := c.service.Backtest(params.Code, params.Type, params.StartTime, params.EndTime, strategy, cfg)
+	if errors.Is(err, backtest.ErrNoBars) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code":       params.Code,
+			"type":       params.Type,
+			"strategy":   params.Strategy,
+			"start_time": params.StartTime,
+			"end_time":   params.EndTime,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": report,
+	})
+}
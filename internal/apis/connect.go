@@ -0,0 +1,70 @@
+package apis
+
+import (
+	"net/http"
+	"stock/pkg/spiders"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ConnectFlowRequest struct {
+	Direction spiders.ConnectDirection `json:"direction" form:"direction" binding:"omitempty,oneof=north south"`
+	StartTime time.Time                `json:"start_time" form:"start_time" time_format:"2006-01-02 15:04:05"`
+	EndTime   time.Time                `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+	Date      string                   `json:"date" form:"date"` // of the top traded stocks, 2006-01-02
+}
+
+// ConnectFlow answers the northbound flows unless direction is south. The history covers
+// the last 30 days unless start_time is given, the top traded stocks the last day unless
+// date is.
+func (c *Controller) ConnectFlow(ctx *gin.Context) {
+	params := new(ConnectFlowRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.Direction == "" {
+		params.Direction = spiders.Northbound
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now()
+	}
+	if params.StartTime.IsZero() {
+		params.StartTime = params.EndTime.AddDate(0, 0, -30)
+	}
+	var date time.Time
+	if params.Date != "" {
+		var err error
+		if date, err = time.Parse("2006-01-02", params.Date); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"code": "400",
+				"msg":  err.Error(),
+			})
+			return
+		}
+	}
+	flow, err := c.service.ConnectFlow(params.Direction, params.StartTime, params.EndTime, date)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"direction":  params.Direction,
+			"start_time": params.StartTime,
+			"end_time":   params.EndTime,
+			"date":       params.Date,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": flow,
+	})
+}
@@ -0,0 +1,68 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ConvertibleBondURI struct {
+	Code string `uri:"code" binding:"required"`
+}
+
+// ConvertibleBonds answers the listed convertible bonds with the quotes of their
+// underlying stocks.
+func (c *Controller) ConvertibleBonds(ctx *gin.Context) {
+	list, err := c.service.ConvertibleBonds()
+	if err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+// ConvertibleBond answers the bond of the code path param, a secid such as 1.113050.
+func (c *Controller) ConvertibleBond(ctx *gin.Context) {
+	uri := new(ConvertibleBondURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	bond, err := c.service.ConvertibleBond(uri.Code)
+	if errors.Is(err, spiders.ErrUnknownConvertibleBond) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code": uri.Code,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": bond,
+	})
+}
@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CorporateActionsRequest struct {
+	Code    string `json:"code" form:"code" binding:"required"`
+	Factors bool   `json:"factors" form:"factors"`
+}
+
+func (c *Controller) CorporateActions(ctx *gin.Context) {
+	params := new(CorporateActionsRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	data, err := c.service.CorporateActions(params.Code, params.Factors)
+	if errors.Is(err, spiders.ErrUnsupportedMarket) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code":    params.Code,
+			"factors": params.Factors,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": data,
+	})
+}
@@ -0,0 +1,92 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// FundNAVRequest takes a fund code or the internal_code of a fund type Search result.
+type FundNAVRequest struct {
+	Code      string    `json:"code" form:"code" binding:"required"`
+	StartTime time.Time `json:"start_time" form:"start_time" time_format:"2006-01-02 15:04:05"`
+	EndTime   time.Time `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+}
+
+// FundNAV answers the NAV history, of the last year unless start_time is given.
+func (c *Controller) FundNAV(ctx *gin.Context) {
+	params := new(FundNAVRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now()
+	}
+	if params.StartTime.IsZero() {
+		params.StartTime = params.EndTime.AddDate(-1, 0, 0)
+	}
+	navs, err := c.service.FundNAV(params.Code, params.StartTime, params.EndTime)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code":       params.Code,
+			"start_time": params.StartTime,
+			"end_time":   params.EndTime,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": navs,
+	})
+}
+
+type FundEstimateRequest struct {
+	Code string `json:"code" form:"code" binding:"required"`
+}
+
+func (c *Controller) FundEstimate(ctx *gin.Context) {
+	params := new(FundEstimateRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	estimate, err := c.service.FundEstimate(params.Code)
+	if errors.Is(err, spiders.ErrNoFundEstimate) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code": params.Code,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": estimate,
+	})
+}
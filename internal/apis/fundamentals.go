@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type FundamentalsRequest struct {
+	Code    string `json:"code" form:"code" binding:"required"`
+	Periods int    `json:"periods" form:"periods" binding:"gte=0,lte=40"`
+}
+
+func (c *Controller) Fundamentals(ctx *gin.Context) {
+	params := new(FundamentalsRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	data, err := c.service.Fundamentals(params.Code, params.Periods)
+	if errors.Is(err, spiders.ErrUnsupportedMarket) || errors.Is(err, spiders.ErrUnsupportedCompany) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code":    params.Code,
+			"periods": params.Periods,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": data,
+	})
+}
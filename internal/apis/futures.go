@@ -0,0 +1,73 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// FuturesURI is a futures product such as rb, or 113.rb for a single exchange.
+type FuturesURI struct {
+	Product string `uri:"product" binding:"required"`
+}
+
+func (c *Controller) FuturesContracts(ctx *gin.Context) {
+	uri := new(FuturesURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	list, err := c.service.FuturesContracts(uri.Product)
+	if err != nil {
+		abortWithFuturesError(ctx, err, logrus.Fields{"product": uri.Product})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+func (c *Controller) MainContract(ctx *gin.Context) {
+	uri := new(FuturesURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	contract, err := c.service.MainContract(uri.Product)
+	if err != nil {
+		abortWithFuturesError(ctx, err, logrus.Fields{"product": uri.Product})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": contract,
+	})
+}
+
+// abortWithFuturesError answers 404 for unknown products and 500 for other errors.
+func abortWithFuturesError(ctx *gin.Context, err error, fields logrus.Fields) {
+	if errors.Is(err, spiders.ErrUnknownProduct) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	logrus.WithFields(fields).Error(err)
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		"code": "500",
+		"msg":  "service internal error",
+	})
+}
@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type IndexURI struct {
+	Code string `uri:"code" binding:"required"`
+}
+
+// IndexConstituents answers the members of the index, or holdings of the ETF, of the
+// code path param, a secid such as 1.000300.
+func (c *Controller) IndexConstituents(ctx *gin.Context) {
+	uri := new(IndexURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	list, err := c.service.IndexConstituents(uri.Code)
+	if errors.Is(err, spiders.ErrUnsupportedIndex) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code": uri.Code,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
@@ -0,0 +1,109 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type LimitBoardController struct {
+	service *services.LimitBoardImpl
+}
+
+func NewLimitBoardController(service *services.LimitBoardImpl) *LimitBoardController {
+	return &LimitBoardController{
+		service: service,
+	}
+}
+
+type LimitBoardRequest struct {
+	Date string `json:"date" form:"date"`
+}
+
+// Board answers the latest saved board, or the one of the date param (2006-01-02).
+func (c *LimitBoardController) Board(ctx *gin.Context) {
+	params := new(LimitBoardRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.Date != "" {
+		if _, err := time.Parse("2006-01-02", params.Date); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"code": "400",
+				"msg":  err.Error(),
+			})
+			return
+		}
+	}
+	board, err := c.service.Board(params.Date)
+	if err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{
+			"date": params.Date,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": board,
+	})
+}
+
+type BillboardRequest struct {
+	Code      string    `json:"code" form:"code"`
+	StartTime time.Time `json:"start_time" form:"start_time" time_format:"2006-01-02 15:04:05"`
+	EndTime   time.Time `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+}
+
+// Billboard answers the 龙虎榜 of the last 7 days unless start_time is given, with the
+// seats when code is given.
+func (c *LimitBoardController) Billboard(ctx *gin.Context) {
+	params := new(BillboardRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now()
+	}
+	if params.StartTime.IsZero() {
+		params.StartTime = params.EndTime.AddDate(0, 0, -7)
+	}
+	list, err := c.service.Billboard(params.Code, params.StartTime, params.EndTime)
+	if errors.Is(err, spiders.ErrUnsupportedMarket) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code":       params.Code,
+			"start_time": params.StartTime,
+			"end_time":   params.EndTime,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
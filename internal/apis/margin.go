@@ -0,0 +1,62 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/pkg/spiders"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// MarginRequest asks the market totals when code is empty.
+type MarginRequest struct {
+	Code      string    `json:"code" form:"code"`
+	StartTime time.Time `json:"start_time" form:"start_time" time_format:"2006-01-02 15:04:05"`
+	EndTime   time.Time `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+}
+
+// Margin answers the margin trading of the last 3 months unless start_time is given, on
+// the labels of the daily kline of the same range.
+func (c *Controller) Margin(ctx *gin.Context) {
+	params := new(MarginRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now()
+	}
+	if params.StartTime.IsZero() {
+		params.StartTime = params.EndTime.AddDate(0, -3, 0)
+	}
+	series, err := c.service.Margin(params.Code, params.StartTime, params.EndTime)
+	if errors.Is(err, spiders.ErrUnsupportedMarket) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code":       params.Code,
+			"start_time": params.StartTime,
+			"end_time":   params.EndTime,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": series,
+	})
+}
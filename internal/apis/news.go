@@ -0,0 +1,72 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type NewsController struct {
+	service *services.NewsImpl
+}
+
+func NewNewsController(service *services.NewsImpl) *NewsController {
+	return &NewsController{
+		service: service,
+	}
+}
+
+type NewsRequest struct {
+	Code     string           `json:"code" form:"code" binding:"required"`
+	Kind     spiders.NewsKind `json:"kind" form:"kind" binding:"omitempty,oneof=news announcement"`
+	Page     int              `json:"page" form:"page" binding:"gte=0"`
+	PageSize int              `json:"page_size" form:"page_size" binding:"gte=0,lte=100"`
+}
+
+func (c *NewsController) List(ctx *gin.Context) {
+	params := new(NewsRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PageSize == 0 {
+		params.PageSize = 20
+	}
+	list, err := c.service.News(params.Code, params.Kind, params.Page, params.PageSize)
+	if errors.Is(err, spiders.ErrUnsupportedMarket) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code": params.Code,
+			"kind": params.Kind,
+			"page": params.Page,
+		}).Error(err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code": "500",
+			"msg":  "service internal error",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":      0,
+		"msg":       "",
+		"page":      params.Page,
+		"page_size": params.PageSize,
+		"list":      list,
+	})
+}
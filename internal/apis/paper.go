@@ -0,0 +1,223 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/internal/entities"
+	"stock/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PaperController struct {
+	service *services.PaperImpl
+}
+
+func NewPaperController(service *services.PaperImpl) *PaperController {
+	return &PaperController{
+		service: service,
+	}
+}
+
+type PaperAccountURI struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+type PaperOrderURI struct {
+	ID      uint64 `uri:"id" binding:"required"`
+	OrderID uint64 `uri:"oid" binding:"required"`
+}
+
+type PaperAccountRequest struct {
+	Name string  `json:"name" form:"name" binding:"required"`
+	Cash float64 `json:"cash" form:"cash" binding:"required,gt=0"`
+}
+
+type PaperOrderRequest struct {
+	Code     string             `json:"code" form:"code" binding:"required"`
+	Side     entities.TradeSide `json:"side" form:"side" binding:"required,oneof=buy sell"`
+	Type     entities.OrderType `json:"type" form:"type" binding:"required,oneof=market limit"`
+	Price    float64            `json:"price" form:"price" binding:"gte=0"`
+	Quantity int64              `json:"quantity" form:"quantity" binding:"required,gt=0"`
+}
+
+func (c *PaperController) abort(ctx *gin.Context, err error, fields logrus.Fields) {
+	if errors.Is(err, services.ErrInvalidOrder) || errors.Is(err, services.ErrOrderNotPending) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	abortWithStoreError(ctx, err, fields)
+}
+
+func (c *PaperController) Accounts(ctx *gin.Context) {
+	list, err := c.service.PaperAccounts()
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+func (c *PaperController) CreateAccount(ctx *gin.Context) {
+	params := new(PaperAccountRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	a, err := c.service.CreatePaperAccount(params.Name, params.Cash)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"name": params.Name,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": a,
+	})
+}
+
+func (c *PaperController) Account(ctx *gin.Context) {
+	uri := new(PaperAccountURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	a, err := c.service.PaperAccount(uri.ID)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": a,
+	})
+}
+
+func (c *PaperController) Orders(ctx *gin.Context) {
+	uri := new(PaperAccountURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	list, err := c.service.PaperOrders(uri.ID)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+func (c *PaperController) PlaceOrder(ctx *gin.Context) {
+	uri := new(PaperAccountURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	params := new(PaperOrderRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	o := &entities.PaperOrder{
+		AccountID: uri.ID,
+		Code:      params.Code,
+		Side:      params.Side,
+		Type:      params.Type,
+		Price:     params.Price,
+		Quantity:  params.Quantity,
+	}
+	if err := c.service.PlaceOrder(o); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id":   uri.ID,
+			"code": params.Code,
+			"side": params.Side,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": o,
+	})
+}
+
+func (c *PaperController) CancelOrder(ctx *gin.Context) {
+	uri := new(PaperOrderURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	o, err := c.service.CancelOrder(uri.ID, uri.OrderID)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id":       uri.ID,
+			"order_id": uri.OrderID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": o,
+	})
+}
+
+func (c *PaperController) Fills(ctx *gin.Context) {
+	uri := new(PaperAccountURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	list, err := c.service.PaperFills(uri.ID)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
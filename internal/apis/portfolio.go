@@ -0,0 +1,228 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/internal/entities"
+	"stock/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PortfolioController struct {
+	service *services.PortfolioImpl
+}
+
+func NewPortfolioController(service *services.PortfolioImpl) *PortfolioController {
+	return &PortfolioController{
+		service: service,
+	}
+}
+
+type PortfolioURI struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+type TransactionURI struct {
+	ID            uint64 `uri:"id" binding:"required"`
+	TransactionID uint64 `uri:"tid" binding:"required"`
+}
+
+type PortfolioRequest struct {
+	Name string `json:"name" form:"name" binding:"required"`
+}
+
+type TransactionRequest struct {
+	Code     string             `json:"code" form:"code" binding:"required"`
+	Side     entities.TradeSide `json:"side" form:"side" binding:"required,oneof=buy sell"`
+	Quantity int64              `json:"quantity" form:"quantity" binding:"required,gt=0"`
+	Price    float64            `json:"price" form:"price" binding:"required,gt=0"`
+	Fee      float64            `json:"fee" form:"fee" binding:"gte=0"`
+	Date     string             `json:"date" form:"date" binding:"required"` // 2006-01-02
+}
+
+func (c *PortfolioController) abort(ctx *gin.Context, err error, fields logrus.Fields) {
+	if errors.Is(err, services.ErrInvalidTransaction) || errors.Is(err, services.ErrInsufficientPosition) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	abortWithStoreError(ctx, err, fields)
+}
+
+func (c *PortfolioController) List(ctx *gin.Context) {
+	list, err := c.service.Portfolios()
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+func (c *PortfolioController) Create(ctx *gin.Context) {
+	params := new(PortfolioRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	p, err := c.service.CreatePortfolio(params.Name)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"name": params.Name,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": p,
+	})
+}
+
+func (c *PortfolioController) Delete(ctx *gin.Context) {
+	uri := new(PortfolioURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err := c.service.DeletePortfolio(uri.ID); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+	})
+}
+
+func (c *PortfolioController) Transactions(ctx *gin.Context) {
+	uri := new(PortfolioURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	list, err := c.service.Transactions(uri.ID)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+func (c *PortfolioController) CreateTransaction(ctx *gin.Context) {
+	uri := new(PortfolioURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	params := new(TransactionRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	date, err := time.ParseInLocation("2006-01-02", params.Date, time.Local)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	t := &entities.Transaction{
+		PortfolioID: uri.ID,
+		Code:        params.Code,
+		Side:        params.Side,
+		Quantity:    params.Quantity,
+		Price:       params.Price,
+		Fee:         params.Fee,
+		Date:        date,
+	}
+	if err := c.service.CreateTransaction(t); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id":   uri.ID,
+			"code": params.Code,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": t,
+	})
+}
+
+func (c *PortfolioController) DeleteTransaction(ctx *gin.Context) {
+	uri := new(TransactionURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err := c.service.DeleteTransaction(uri.ID, uri.TransactionID); err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id":             uri.ID,
+			"transaction_id": uri.TransactionID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+	})
+}
+
+func (c *PortfolioController) Valuation(ctx *gin.Context) {
+	uri := new(PortfolioURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	valuation, err := c.service.Valuation(uri.ID)
+	if err != nil {
+		c.abort(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": valuation,
+	})
+}
@@ -2,8 +2,11 @@ package apis
 
 import (
 	"net/http"
+	"stock/internal/notify"
 	"stock/internal/services"
+	"stock/internal/store"
 	"stock/pkg/spiders"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
@@ -11,7 +14,20 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func Route(port string) {
+type Options struct {
+	Port          string
+	DB            *store.Store
+	Notifiers     []notify.Notifier
+	AlertInterval time.Duration // 0 disables alert polling
+	PaperInterval time.Duration // 0 disables matching pending paper orders
+	LimitInterval time.Duration // 0 disables refreshing the limit board, which is only scanned in the background
+	NewsInterval  time.Duration // 0 disables polling the announcements of watchlist stocks
+	// SymbolInterval is how often the local symbol master searched by /api/search is
+	// listed again, 0 only uses the saved one.
+	SymbolInterval time.Duration
+}
+
+func Route(opts Options) {
 	router := gin.Default()
 
 	corsConfig := cors.DefaultConfig()
@@ -19,8 +35,38 @@ func Route(port string) {
 	router.Use(cors.New(corsConfig))
 
 	router.Use(gzip.Gzip(gzip.DefaultCompression))
-	service := services.NewService(&spiders.EastMoneyProvider{})
+	provider := &spiders.EastMoneyProvider{}
+	symbols := services.NewSymbolMaster(opts.DB, provider)
+	if err := symbols.Load(); err != nil {
+		logrus.Error(err)
+	}
+	if opts.SymbolInterval > 0 {
+		go symbols.Run(opts.SymbolInterval, nil)
+	}
+	service := services.NewService(provider, opts.DB).WithSymbols(symbols)
 	ctl := NewController(service)
+	watchlistCtl := NewWatchlistController(services.NewWatchlistService(opts.DB, service))
+	alertService := services.NewAlertService(opts.DB, service, opts.Notifiers...)
+	alertCtl := NewAlertController(alertService)
+	portfolioCtl := NewPortfolioController(services.NewPortfolioService(opts.DB, service))
+	paperService := services.NewPaperService(opts.DB, service)
+	paperCtl := NewPaperController(paperService)
+	if opts.PaperInterval > 0 {
+		go paperService.Run(opts.PaperInterval, nil)
+	}
+	limitService := services.NewLimitBoardService(opts.DB, service)
+	limitCtl := NewLimitBoardController(limitService)
+	if opts.LimitInterval > 0 {
+		go limitService.Run(opts.LimitInterval, nil)
+	}
+	newsService := services.NewNewsService(opts.DB, service, alertService)
+	newsCtl := NewNewsController(newsService)
+	if opts.NewsInterval > 0 {
+		go newsService.Run(opts.NewsInterval, watchedCodes(opts.DB), nil)
+	}
+	if opts.AlertInterval > 0 {
+		go alertService.Run(opts.AlertInterval, nil)
+	}
 
 	router.GET("health", func(context *gin.Context) {
 		context.Status(http.StatusOK)
@@ -31,15 +77,82 @@ func Route(port string) {
 	gRouter.GET("trend", ctl.Trend)
 
 	gRouter.GET("kline", ctl.KLine)
+	gRouter.GET("kline/types", ctl.KLineTypes)
 	gRouter.GET("search", ctl.Search)
 	gRouter.GET("stock", ctl.Stock)
 	gRouter.GET("multi_stock", ctl.MultiStock)
+	gRouter.GET("backtest", ctl.Backtest)
+	gRouter.GET("analytics", ctl.Analytics)
+	gRouter.GET("limit_board", limitCtl.Board)
+	gRouter.GET("billboard", limitCtl.Billboard)
+	gRouter.GET("fundamentals", ctl.Fundamentals)
+	gRouter.GET("corporate_actions", ctl.CorporateActions)
+	gRouter.GET("index/:code/constituents", ctl.IndexConstituents)
+	gRouter.GET("news", newsCtl.List)
+	gRouter.GET("fund/nav", ctl.FundNAV)
+	gRouter.GET("fund/estimate", ctl.FundEstimate)
+	gRouter.GET("convertible_bonds", ctl.ConvertibleBonds)
+	gRouter.GET("convertible_bonds/:code", ctl.ConvertibleBond)
+	gRouter.GET("futures/:product/contracts", ctl.FuturesContracts)
+	gRouter.GET("futures/:product/main", ctl.MainContract)
+	gRouter.GET("connect_flow", ctl.ConnectFlow)
+	gRouter.GET("margin", ctl.Margin)
 
-	if err := router.Run(port); err != nil {
+	gRouter.GET("watchlists", watchlistCtl.List)
+	gRouter.POST("watchlists", watchlistCtl.Create)
+	gRouter.GET("watchlists/:id", watchlistCtl.Get)
+	gRouter.PUT("watchlists/:id", watchlistCtl.Update)
+	gRouter.DELETE("watchlists/:id", watchlistCtl.Delete)
+	gRouter.GET("watchlists/:id/quotes", watchlistCtl.Quotes)
+
+	gRouter.GET("alerts", alertCtl.List)
+	gRouter.POST("alerts", alertCtl.Create)
+	gRouter.PUT("alerts/:id", alertCtl.Update)
+	gRouter.DELETE("alerts/:id", alertCtl.Delete)
+	gRouter.GET("alert_events", alertCtl.Events)
+
+	gRouter.GET("portfolios", portfolioCtl.List)
+	gRouter.POST("portfolios", portfolioCtl.Create)
+	gRouter.DELETE("portfolios/:id", portfolioCtl.Delete)
+	gRouter.GET("portfolios/:id/transactions", portfolioCtl.Transactions)
+	gRouter.POST("portfolios/:id/transactions", portfolioCtl.CreateTransaction)
+	gRouter.DELETE("portfolios/:id/transactions/:tid", portfolioCtl.DeleteTransaction)
+	gRouter.GET("portfolios/:id/valuation", portfolioCtl.Valuation)
+
+	gRouter.GET("paper/accounts", paperCtl.Accounts)
+	gRouter.POST("paper/accounts", paperCtl.CreateAccount)
+	gRouter.GET("paper/accounts/:id", paperCtl.Account)
+	gRouter.GET("paper/accounts/:id/orders", paperCtl.Orders)
+	gRouter.POST("paper/accounts/:id/orders", paperCtl.PlaceOrder)
+	gRouter.DELETE("paper/accounts/:id/orders/:oid", paperCtl.CancelOrder)
+	gRouter.GET("paper/accounts/:id/fills", paperCtl.Fills)
+
+	if err := router.Run(opts.Port); err != nil {
 		logrus.Panicln(err)
 	}
 }
 
+// watchedCodes lists the distinct codes of all watchlists.
+func watchedCodes(db *store.Store) func() ([]string, error) {
+	return func() ([]string, error) {
+		lists, err := db.Watchlists()
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		codes := make([]string, 0)
+		for _, w := range lists {
+			for _, code := range w.Codes {
+				if !seen[code] {
+					seen[code] = true
+					codes = append(codes, code)
+				}
+			}
+		}
+		return codes, nil
+	}
+}
+
 type Controller struct {
 	service *services.StockImpl
 }
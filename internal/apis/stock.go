@@ -1,8 +1,11 @@
 package apis
 
 import (
+	"fmt"
 	"net/http"
+	"stock/pkg/resample"
 	"stock/pkg/spiders"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -45,7 +48,10 @@ func (c *Controller) Trend(ctx *gin.Context) {
 }
 
 type SearchRequest struct {
-	Key string `json:"key" form:"key"`
+	Key      string                 `json:"key" form:"key" binding:"required"`
+	Page     int                    `json:"page" form:"page" binding:"gte=0"`
+	PageSize int                    `json:"page_size" form:"page_size" binding:"gte=0,lte=50"`
+	Types    []spiders.SecurityType `json:"types" form:"types[]"`
 }
 
 func (c *Controller) Search(ctx *gin.Context) {
@@ -57,7 +63,28 @@ func (c *Controller) Search(ctx *gin.Context) {
 		})
 		return
 	}
-	stocks, err := c.service.Search(params.Key)
+	params.Key = strings.TrimSpace(params.Key)
+	if params.Key == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  "key must not be blank",
+		})
+		return
+	}
+	for _, t := range params.Types {
+		if !t.Valid() {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"code": "400",
+				"msg":  fmt.Sprintf("unknown security type [%s]", t),
+			})
+			return
+		}
+	}
+	stocks, err := c.service.Search(params.Key, spiders.SearchOptions{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+		Types:    params.Types,
+	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"code": params.Key,
@@ -80,6 +107,7 @@ type KLineRequest struct {
 	Type      spiders.Type `json:"type" form:"type"`
 	StartTime time.Time    `json:"start_time" form:"start_time" binding:"required" time_format:"2006-01-02 15:04:05"`
 	EndTime   time.Time    `json:"end_time" form:"end_time" time_format:"2006-01-02 15:04:05"`
+	Patterns  bool         `json:"patterns" form:"patterns"`
 }
 
 func (c *Controller) KLine(ctx *gin.Context) {
@@ -94,10 +122,17 @@ func (c *Controller) KLine(ctx *gin.Context) {
 	if params.Type == "" {
 		params.Type = spiders.OneHour
 	}
+	if _, err := resample.ParsePeriod(string(params.Type)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
 	if params.EndTime.IsZero() {
 		params.EndTime = time.Now()
 	}
-	kline, err := c.service.KLine(params.Code, params.Type, params.StartTime, params.EndTime)
+	kline, err := c.service.KLine(params.Code, params.Type, params.StartTime, params.EndTime, params.Patterns)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"code":       params.Code,
@@ -118,6 +153,29 @@ func (c *Controller) KLine(ctx *gin.Context) {
 	})
 }
 
+// KLineTypes lists the periods the provider serves, and the grammar of the custom periods
+// resampled from them, such as 2h or 2w.
+func (c *Controller) KLineTypes(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": spiders.SupportedTypes,
+		"custom": gin.H{
+			"pattern": resample.PeriodPattern,
+			"units": gin.H{
+				"min": "minute",
+				"h":   "hour",
+				"d":   "day",
+				"w":   "week",
+				"m":   "month",
+				"q":   "quarter",
+				"y":   "year",
+			},
+			"examples": []string{"2min", "2h", "3d", "2w", "6m"},
+		},
+	})
+}
+
 type StockRequest struct {
 	Code string `json:"code" form:"code"`
 }
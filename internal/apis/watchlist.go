@@ -0,0 +1,183 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"stock/internal/services"
+	"stock/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type WatchlistController struct {
+	service *services.WatchlistImpl
+}
+
+func NewWatchlistController(service *services.WatchlistImpl) *WatchlistController {
+	return &WatchlistController{
+		service: service,
+	}
+}
+
+type WatchlistURI struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+type WatchlistRequest struct {
+	Name  string   `json:"name" form:"name" binding:"required"`
+	Codes []string `json:"codes" form:"codes[]" binding:"dive,required"`
+}
+
+// abortWithStoreError answers 404 for missing records and 500 otherwise.
+func abortWithStoreError(ctx *gin.Context, err error, fields logrus.Fields) {
+	if errors.Is(err, store.ErrNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"code": "404",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	logrus.WithFields(fields).Error(err)
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		"code": "500",
+		"msg":  "service internal error",
+	})
+}
+
+func (c *WatchlistController) List(ctx *gin.Context) {
+	list, err := c.service.Watchlists()
+	if err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": list,
+	})
+}
+
+func (c *WatchlistController) Create(ctx *gin.Context) {
+	params := new(WatchlistRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	w, err := c.service.CreateWatchlist(params.Name, params.Codes)
+	if err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{
+			"name":  params.Name,
+			"codes": params.Codes,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": w,
+	})
+}
+
+func (c *WatchlistController) Get(ctx *gin.Context) {
+	uri := new(WatchlistURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	w, err := c.service.Watchlist(uri.ID)
+	if err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": w,
+	})
+}
+
+func (c *WatchlistController) Update(ctx *gin.Context) {
+	uri := new(WatchlistURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	params := new(WatchlistRequest)
+	if err := ctx.Bind(params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	w, err := c.service.UpdateWatchlist(uri.ID, params.Name, params.Codes)
+	if err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{
+			"id":    uri.ID,
+			"name":  params.Name,
+			"codes": params.Codes,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"data": w,
+	})
+}
+
+func (c *WatchlistController) Delete(ctx *gin.Context) {
+	uri := new(WatchlistURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	if err := c.service.DeleteWatchlist(uri.ID); err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+	})
+}
+
+func (c *WatchlistController) Quotes(ctx *gin.Context) {
+	uri := new(WatchlistURI)
+	if err := ctx.BindUri(uri); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"code": "400",
+			"msg":  err.Error(),
+		})
+		return
+	}
+	stocks, err := c.service.Quotes(uri.ID)
+	if err != nil {
+		abortWithStoreError(ctx, err, logrus.Fields{
+			"id": uri.ID,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "",
+		"list": stocks,
+	})
+}
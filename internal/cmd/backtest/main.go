@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"stock/internal/services"
+	"stock/internal/store"
+	"stock/pkg/backtest"
+	"stock/pkg/spiders"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	code         = flag.String("code", "", "secid of the security, e.g. 1.600350")
+	kLineType    = flag.String("type", string(spiders.OneDay), "kline period")
+	start        = flag.String("start", "", "first day, 2006-01-02")
+	end          = flag.String("end", "", "last day, 2006-01-02, defaults to today")
+	strategyName = flag.String("strategy", "sma_cross", "one of "+strings.Join(backtest.StrategyNames, ", "))
+	fast         = flag.Int("fast", 0, "fast window of sma_cross")
+	slow         = flag.Int("slow", 0, "slow window of sma_cross")
+	cash         = flag.Float64("cash", 0, "initial cash, defaults to the rules of the market")
+	dbPath       = flag.String("db", "", "path of the embedded database to read stored klines from")
+	output       = flag.String("output", "summary", "summary or json")
+)
+
+func main() {
+	flag.Parse()
+	if *code == "" || *start == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	startTime, err := time.ParseInLocation("2006-01-02", *start, time.Local)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+	endTime := time.Now()
+	if *end != "" {
+		if endTime, err = time.ParseInLocation("2006-01-02", *end, time.Local); err != nil {
+			logrus.Fatalln(err)
+		}
+	}
+	strategy, err := backtest.NewStrategy(*strategyName, map[string]int{"fast": *fast, "slow": *slow})
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+	cfg := backtest.DefaultConfig(*code)
+	if *cash > 0 {
+		cfg.Cash = *cash
+	}
+
+	var kLineStore services.KLineStore
+	if *dbPath != "" {
+		db, err := store.Open(*dbPath)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+		defer db.Close()
+		kLineStore = db
+	}
+	service := services.NewService(&spiders.EastMoneyProvider{}, kLineStore)
+	report, err := service.Backtest(*code, spiders.Type(*kLineType), startTime, endTime, strategy, cfg)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			logrus.Fatalln(err)
+		}
+		return
+	}
+	for _, trade := range report.Trades {
+		fmt.Printf("%s %-4s %8d @ %.3f  fee %.2f  profit %.2f\n",
+			trade.Time.Format("2006-01-02 15:04"), trade.Side, trade.Quantity, trade.Price, trade.Commission+trade.Tax, trade.Profit)
+	}
+	for _, rejection := range report.Rejections {
+		fmt.Printf("%s %-4s %8d rejected: %s\n", rejection.Time.Format("2006-01-02 15:04"), rejection.Side, rejection.Quantity, rejection.Reason)
+	}
+	fmt.Printf("final equity %.2f  return %.2f%%  max drawdown %.2f%%\n",
+		report.FinalEquity, report.Return*100, report.MaxDrawdown*100)
+}
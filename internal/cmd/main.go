@@ -1,16 +1,74 @@
 package main
 
 import (
+	"flag"
+	"net/smtp"
 	"os"
 	"stock/internal/apis"
+	"stock/internal/notify"
+	"stock/internal/store"
+	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	dbPath         = flag.String("db", "stock.db", "path of the embedded database")
+	alertInterval  = flag.Duration("alert-interval", 0, "how often to evaluate alert rules, 0 to disable")
+	paperInterval  = flag.Duration("paper-interval", 5*time.Second, "how often to match pending paper orders, 0 to disable")
+	limitInterval  = flag.Duration("limit-interval", time.Minute, "how often to rescan the limit up/down board while the market is open, 0 to disable")
+	newsInterval   = flag.Duration("news-interval", 0, "how often to poll the announcements of watchlist stocks into alert events, 0 to disable")
+	symbolInterval = flag.Duration("symbol-interval", 24*time.Hour, "how often to list all securities into the local search, 0 to only use the saved list")
+	webhookURL     = flag.String("webhook", "", "URL alert events are posted to")
+	smtpAddr       = flag.String("smtp-addr", "", "host:port of the SMTP server alert events are mailed through")
+	smtpUser       = flag.String("smtp-user", "", "SMTP user, empty for no authentication")
+	smtpPassword   = flag.String("smtp-password", "", "SMTP password")
+	smtpFrom       = flag.String("smtp-from", "", "sender of alert mails")
+	smtpTo         = flag.String("smtp-to", "", "comma separated recipients of alert mails")
+)
+
+func notifiers() []notify.Notifier {
+	list := []notify.Notifier{notify.Log{}}
+	if *webhookURL != "" {
+		list = append(list, notify.NewWebhook(*webhookURL))
+	}
+	if *smtpAddr != "" {
+		mailer := &notify.SMTP{
+			Addr: *smtpAddr,
+			From: *smtpFrom,
+			To:   strings.Split(*smtpTo, ","),
+		}
+		if *smtpUser != "" {
+			host := strings.Split(*smtpAddr, ":")[0]
+			mailer.Auth = smtp.PlainAuth("", *smtpUser, *smtpPassword, host)
+		}
+		list = append(list, mailer)
+	}
+	return list
+}
+
 func main() {
+	flag.Parse()
 	logrus.SetOutput(os.Stdout)
 	logrus.SetLevel(logrus.InfoLevel)
 	logrus.SetFormatter(&logrus.TextFormatter{})
 
-	apis.Route(":8080")
+	db, err := store.Open(*dbPath)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+	defer db.Close()
+
+	apis.Route(apis.Options{
+		Port:           ":8080",
+		DB:             db,
+		Notifiers:      notifiers(),
+		AlertInterval:  *alertInterval,
+		PaperInterval:  *paperInterval,
+		LimitInterval:  *limitInterval,
+		NewsInterval:   *newsInterval,
+		SymbolInterval: *symbolInterval,
+	})
 }
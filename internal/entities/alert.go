@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+type AlertKind string
+
+const (
+	AlertPriceAbove       AlertKind = "price_above"
+	AlertPriceBelow       AlertKind = "price_below"
+	AlertGainAbove        AlertKind = "gain_above"   // 涨幅(%) >= threshold
+	AlertGainBelow        AlertKind = "gain_below"   // 涨幅(%) <= threshold, e.g. -5
+	AlertLimitUp          AlertKind = "limit_up"     // touches 涨停, threshold unused
+	AlertLimitDown        AlertKind = "limit_down"   // touches 跌停, threshold unused
+	AlertVolumeRatioAbove AlertKind = "volume_ratio" // 量比 >= threshold
+	AlertAnnouncement     AlertKind = "announcement" // raised by feeds, not by rules
+)
+
+// AlertKinds lists the kinds a rule can be created with.
+var AlertKinds = []AlertKind{
+	AlertPriceAbove, AlertPriceBelow, AlertGainAbove, AlertGainBelow,
+	AlertLimitUp, AlertLimitDown, AlertVolumeRatioAbove,
+}
+
+type AlertRule struct {
+	ID        uint64    `json:"id"`
+	Code      string    `json:"code"`
+	Kind      AlertKind `json:"kind"`
+	Threshold float64   `json:"threshold"`
+	Cooldown  int64     `json:"cooldown"`  // seconds before the rule may fire again
+	Notifiers []string  `json:"notifiers"` // notifier names, empty for all
+	Enabled   bool      `json:"enabled"`
+	// Active is whether the condition held at the last evaluation, rules only fire when
+	// the condition starts to hold.
+	Active      bool      `json:"active"`
+	TriggeredAt time.Time `json:"triggered_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AlertEvent struct {
+	ID        uint64    `json:"id"`
+	RuleID    uint64    `json:"rule_id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Kind      AlertKind `json:"kind"`
+	Threshold float64   `json:"threshold"`
+	Value     float64   `json:"value"`
+	Message   string    `json:"message"`
+	URL       string    `json:"url,omitempty"`
+	Time      time.Time `json:"time"`
+}
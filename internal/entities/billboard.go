@@ -0,0 +1,10 @@
+package entities
+
+import "stock/pkg/spiders"
+
+// BillboardEntry is a 龙虎榜 entry with the limit the stock reached that day, nil when
+// the saved limit board of the day does not list it or was not recorded.
+type BillboardEntry struct {
+	*spiders.BillboardEntry
+	Limit *LimitStock `json:"limit"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "stock/pkg/spiders"
+
+// ConnectFlow is the Stock Connect capital of a direction: the minutes of the last
+// trading day, the daily history and the top traded stocks of a day.
+type ConnectFlow struct {
+	Direction spiders.ConnectDirection   `json:"direction"`
+	Intraday  *spiders.ConnectIntraday   `json:"intraday"`
+	History   []*spiders.ConnectFlow     `json:"history"`
+	Top       []*spiders.ConnectTopStock `json:"top"`
+}
@@ -0,0 +1,10 @@
+package entities
+
+import "stock/pkg/spiders"
+
+// Constituent is an index member or ETF holding with its live quote, nil when the
+// provider has none.
+type Constituent struct {
+	*spiders.Constituent
+	Quote *spiders.MultiStock `json:"quote"`
+}
@@ -0,0 +1,10 @@
+package entities
+
+import "stock/pkg/spiders"
+
+// ConvertibleBond is a convertible bond with the live quote of its underlying stock, nil
+// when the provider has none.
+type ConvertibleBond struct {
+	*spiders.ConvertibleBond
+	Stock *spiders.MultiStock `json:"stock"`
+}
@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"stock/pkg/adjust"
+	"stock/pkg/spiders"
+)
+
+// CorporateActions are the dividend plans and rights issues of a stock, with the factor of each
+// executed one when asked for. Multiplying the factors from an ex-date on gives the
+// backward adjustment of the prices since it.
+type CorporateActions struct {
+	Code    string                     `json:"code"`
+	Actions []*spiders.CorporateAction `json:"actions"`
+	Factors []*adjust.Event            `json:"factors,omitempty"`
+}
@@ -1,6 +1,9 @@
 package entities
 
+import "stock/pkg/pattern"
+
 type KLine struct {
-	Labels []string    `json:"labels"`
-	KLine  [][]float64 `json:"k_line"`
+	Labels   []string              `json:"labels"`
+	KLine    [][]float64           `json:"k_line"`
+	Patterns []*pattern.Occurrence `json:"patterns,omitempty"`
 }
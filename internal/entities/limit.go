@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+type LimitKind string
+
+const (
+	LimitUp   LimitKind = "up"
+	LimitDown LimitKind = "down"
+)
+
+// LimitStock is a stock that reached its daily price limit during the day.
+type LimitStock struct {
+	Code           string      `json:"code"`
+	Name           string      `json:"name"`
+	Kind           LimitKind   `json:"kind"`
+	Price          float64     `json:"price"`
+	LimitPrice     float64     `json:"limit_price"`
+	Gains          float64     `json:"gains"`
+	TurnoverAmount float64     `json:"turnover_amount"`
+	TurnoverRate   float64     `json:"turnover_rate"`
+	Circulation    float64     `json:"circulation"`
+	Sealed         bool        `json:"sealed"`      // at the limit when scanned, otherwise the board is broken (炸板)
+	Consecutive    int         `json:"consecutive"` // limit up closes in a row ending today (连板), 0 for limit down
+	FirstTouch     time.Time   `json:"first_touch"`
+	LastTouch      time.Time   `json:"last_touch"`
+	Opens          []time.Time `json:"opens"` // minutes the price left the limit after touching it (开板)
+}
+
+// LimitBoard is the limit up/down board of one trading day.
+type LimitBoard struct {
+	Date      string        `json:"date"` // 2006-01-02 in China time
+	UpdatedAt time.Time     `json:"updated_at"`
+	Up        []*LimitStock `json:"up"`
+	Down      []*LimitStock `json:"down"`
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "stock/pkg/spiders"
+
+// MarginSeries is the margin trading on the labels of the daily KLine of the same range,
+// nil on the days without it.
+type MarginSeries struct {
+	Code   string            `json:"code"` // empty for the market totals
+	Labels []string          `json:"labels"`
+	Margin []*spiders.Margin `json:"margin"`
+}
@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+type OrderType string
+
+const (
+	OrderMarket OrderType = "market"
+	OrderLimit  OrderType = "limit"
+)
+
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderFilled    OrderStatus = "filled"
+	OrderCancelled OrderStatus = "cancelled"
+	OrderRejected  OrderStatus = "rejected"
+	// OrderExpired is a day order left pending when its trading day closed.
+	OrderExpired OrderStatus = "expired"
+)
+
+type PaperPosition struct {
+	Code        string    `json:"code"`
+	Quantity    int64     `json:"quantity"`
+	Frozen      int64     `json:"frozen"`       // held by pending sell orders
+	TodayBought int64     `json:"today_bought"` // bought on BoughtOn, not sellable that day under T+1
+	BoughtOn    time.Time `json:"bought_on"`
+	Cost        float64   `json:"cost"` // fees included
+}
+
+// PaperAccount is a simulated trading account. Cash excludes FrozenCash reserved by
+// pending buy orders.
+type PaperAccount struct {
+	ID          uint64                    `json:"id"`
+	Name        string                    `json:"name"`
+	InitialCash float64                   `json:"initial_cash"`
+	Cash        float64                   `json:"cash"`
+	FrozenCash  float64                   `json:"frozen_cash"`
+	Positions   map[string]*PaperPosition `json:"positions"`
+	CreatedAt   time.Time                 `json:"created_at"`
+}
+
+type PaperOrder struct {
+	ID          uint64      `json:"id"`
+	AccountID   uint64      `json:"account_id"`
+	Code        string      `json:"code"`
+	Side        TradeSide   `json:"side"`
+	Type        OrderType   `json:"type"`
+	Price       float64     `json:"price"` // limit price
+	Quantity    int64       `json:"quantity"`
+	Status      OrderStatus `json:"status"`
+	Reason      string      `json:"reason,omitempty"`
+	FilledPrice float64     `json:"filled_price"`
+	Reserved    float64     `json:"reserved"` // cash frozen by a pending buy
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type PaperFill struct {
+	ID         uint64    `json:"id"`
+	AccountID  uint64    `json:"account_id"`
+	OrderID    uint64    `json:"order_id"`
+	Code       string    `json:"code"`
+	Side       TradeSide `json:"side"`
+	Price      float64   `json:"price"`
+	Quantity   int64     `json:"quantity"`
+	Commission float64   `json:"commission"`
+	Tax        float64   `json:"tax"`
+	Time       time.Time `json:"time"`
+}
@@ -0,0 +1,67 @@
+package entities
+
+import "time"
+
+type TradeSide string
+
+const (
+	SideBuy  TradeSide = "buy"
+	SideSell TradeSide = "sell"
+)
+
+type Portfolio struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Transaction is a recorded trade of a portfolio, holdings are derived from them.
+type Transaction struct {
+	ID          uint64    `json:"id"`
+	PortfolioID uint64    `json:"portfolio_id"`
+	Code        string    `json:"code"`
+	Side        TradeSide `json:"side"`
+	Quantity    int64     `json:"quantity"`
+	Price       float64   `json:"price"`
+	Fee         float64   `json:"fee"`
+	Date        time.Time `json:"date"`
+}
+
+type Position struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Sector        string  `json:"sector"`
+	Currency      string  `json:"currency"`
+	Quantity      int64   `json:"quantity"`
+	AvgCost       float64 `json:"avg_cost"` // per share, buy fees included
+	Cost          float64 `json:"cost"`
+	Priced        bool    `json:"priced"` // false without a quote, the values below are then 0
+	Price         float64 `json:"price"`
+	PrevClose     float64 `json:"prev_close"`
+	MarketValue   float64 `json:"market_value"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	UnrealizedPct float64 `json:"unrealized_pct"`
+	RealizedPnL   float64 `json:"realized_pnl"`
+	DailyChange   float64 `json:"daily_change"`
+	Weight        float64 `json:"weight"` // share of the portfolio market value
+}
+
+type Allocation struct {
+	Sector      string  `json:"sector"`
+	MarketValue float64 `json:"market_value"`
+	Weight      float64 `json:"weight"`
+}
+
+// Valuation sums positions as they are, without converting between currencies. The
+// unpriced positions only count in RealizedPnL.
+type Valuation struct {
+	PortfolioID   uint64        `json:"portfolio_id"`
+	MarketValue   float64       `json:"market_value"`
+	Cost          float64       `json:"cost"`
+	UnrealizedPnL float64       `json:"unrealized_pnl"`
+	RealizedPnL   float64       `json:"realized_pnl"`
+	DailyChange   float64       `json:"daily_change"`
+	Positions     []*Position   `json:"positions"`
+	Allocation    []*Allocation `json:"allocation"`
+	Time          time.Time     `json:"time"`
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+type Watchlist struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	Codes     []string  `json:"codes"` // secids in display order
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
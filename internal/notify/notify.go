@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"stock/internal/entities"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier delivers alert events to one channel.
+type Notifier interface {
+	Name() string
+	Notify(event *entities.AlertEvent) error
+}
+
+type Log struct{}
+
+func (Log) Name() string {
+	return "log"
+}
+
+func (Log) Notify(event *entities.AlertEvent) error {
+	logrus.WithFields(logrus.Fields{
+		"rule_id": event.RuleID,
+		"code":    event.Code,
+		"kind":    event.Kind,
+		"value":   event.Value,
+	}).Info(event.Message)
+	return nil
+}
+
+// Webhook posts the event as JSON to URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+func (w *Webhook) Notify(event *entities.AlertEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// SMTP mails the event, Auth may be nil for relays without authentication.
+type SMTP struct {
+	Addr string
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+func (s *SMTP) Name() string {
+	return "smtp"
+}
+
+func (s *SMTP) Notify(event *entities.AlertEvent) error {
+	body := new(bytes.Buffer)
+	fmt.Fprintf(body, "From: %s\r\n", s.From)
+	fmt.Fprintf(body, "To: %s\r\n", strings.Join(s.To, ", "))
+	// names are Chinese, headers must be ASCII (RFC 2047)
+	subject := fmt.Sprintf("[%s] %s %s", event.Kind, event.Code, event.Name)
+	fmt.Fprintf(body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(body, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(body, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(body, "Content-Transfer-Encoding: 8bit\r\n\r\n")
+	fmt.Fprintf(body, "%s\r\n", event.Message)
+	if event.URL != "" {
+		fmt.Fprintf(body, "%s\r\n", event.URL)
+	}
+	fmt.Fprintf(body, "%s\r\n", event.Time.Format("2006-01-02 15:04:05"))
+	return smtp.SendMail(s.Addr, s.Auth, s.From, s.To, body.Bytes())
+}
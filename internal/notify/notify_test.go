@@ -0,0 +1,120 @@
+package notify_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"stock/internal/entities"
+	"stock/internal/notify"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var event = &entities.AlertEvent{
+	ID:      1,
+	RuleID:  2,
+	Code:    "1.600350",
+	Name:    "山东高速",
+	Kind:    entities.AlertPriceAbove,
+	Value:   6.5,
+	Message: "山东高速(1.600350) price 6.500 is above 6.400",
+	Time:    time.Date(2020, 11, 2, 10, 0, 0, 0, time.UTC),
+}
+
+func TestWebhook_Notify(t *testing.T) {
+	received := make(chan *entities.AlertEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := new(entities.AlertEvent)
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- got
+	}))
+	defer server.Close()
+
+	assert.NoError(t, notify.NewWebhook(server.URL).Notify(event))
+	got := <-received
+	assert.Equal(t, event.Message, got.Message)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, notify.NewWebhook(failing.URL).Notify(event))
+}
+
+// serveSMTP answers one SMTP session and sends the DATA it received.
+func serveSMTP(t *testing.T, l net.Listener, data chan<- string) {
+	conn, err := l.Accept()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) {
+		conn.Write([]byte(s + "\r\n"))
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			body := new(strings.Builder)
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				body.WriteString(line)
+			}
+			data <- body.String()
+			reply("250 ok")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSMTP_Notify(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	data := make(chan string, 1)
+	go serveSMTP(t, l, data)
+
+	mailer := &notify.SMTP{Addr: l.Addr().String(), From: "alert@localhost", To: []string{"me@localhost"}}
+	if assert.NoError(t, mailer.Notify(event)) {
+		body := <-data
+		assert.Contains(t, body, "MIME-Version: 1.0\r\n")
+		assert.Contains(t, body, "Content-Type: text/plain; charset=utf-8\r\n")
+		start := strings.Index(body, "Subject: ") + len("Subject: ")
+		subject, err := new(mime.WordDecoder).DecodeHeader(body[start : start+strings.Index(body[start:], "\r\n")])
+		if assert.NoError(t, err) {
+			assert.Equal(t, "[price_above] 1.600350 "+event.Name, subject)
+		}
+		assert.Contains(t, body, event.Message)
+	}
+}
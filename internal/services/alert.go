@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"stock/internal/entities"
+	"stock/internal/notify"
+	"stock/pkg/spiders"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidAlertKind = errors.New("invalid alert kind")
+
+// AlertStore persists alert rules and the events they raised, see store.Store.
+type AlertStore interface {
+	CreateAlertRule(rule *entities.AlertRule) error
+	AlertRules() ([]*entities.AlertRule, error)
+	AlertRule(id uint64) (*entities.AlertRule, error)
+	UpdateAlertRule(rule *entities.AlertRule) error
+	DeleteAlertRule(id uint64) error
+	CreateAlertEvent(event *entities.AlertEvent) error
+	AlertEvents(limit int) ([]*entities.AlertEvent, error)
+}
+
+type AlertImpl struct {
+	AlertStore
+	stock     *StockImpl
+	notifiers []notify.Notifier
+	// mu serializes evaluations with rule changes, so that an evaluation does not write
+	// back a rule edited meanwhile.
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+func NewAlertService(store AlertStore, stock *StockImpl, notifiers ...notify.Notifier) *AlertImpl {
+	return &AlertImpl{
+		AlertStore: store,
+		stock:      stock,
+		notifiers:  notifiers,
+		now:        time.Now,
+	}
+}
+
+func validAlertKind(kind entities.AlertKind) bool {
+	for _, k := range entities.AlertKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AlertImpl) CreateAlertRule(rule *entities.AlertRule) error {
+	if !validAlertKind(rule.Kind) {
+		return fmt.Errorf("%w [%s]", ErrInvalidAlertKind, rule.Kind)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule.Active = false
+	rule.TriggeredAt = time.Time{}
+	return s.AlertStore.CreateAlertRule(rule)
+}
+
+// UpdateAlertRule replaces a rule and re-arms it.
+func (s *AlertImpl) UpdateAlertRule(rule *entities.AlertRule) error {
+	if !validAlertKind(rule.Kind) {
+		return fmt.Errorf("%w [%s]", ErrInvalidAlertKind, rule.Kind)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule.Active = false
+	return s.AlertStore.UpdateAlertRule(rule)
+}
+
+func (s *AlertImpl) DeleteAlertRule(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.AlertStore.DeleteAlertRule(id)
+}
+
+// matchAlertRule returns the value the rule looks at and whether its condition holds.
+// Rules never hold on placeholders, as for suspended stocks.
+func matchAlertRule(rule *entities.AlertRule, detail *spiders.StockWithDetail) (float64, bool) {
+	if detail.Price == nil {
+		return 0, false
+	}
+	price := *detail.Price
+	switch rule.Kind {
+	case entities.AlertPriceAbove:
+		return price, price > 0 && price >= rule.Threshold
+	case entities.AlertPriceBelow:
+		return price, price > 0 && price <= rule.Threshold
+	case entities.AlertGainAbove:
+		gains := spiders.Value(detail.Gains)
+		return gains, detail.Gains != nil && gains >= rule.Threshold
+	case entities.AlertGainBelow:
+		gains := spiders.Value(detail.Gains)
+		return gains, detail.Gains != nil && gains <= rule.Threshold
+	case entities.AlertLimitUp:
+		return price, detail.LimitUp > 0 && price >= detail.LimitUp
+	case entities.AlertLimitDown:
+		return price, detail.LimitDown > 0 && price > 0 && price <= detail.LimitDown
+	case entities.AlertVolumeRatioAbove:
+		ratio := spiders.Value(detail.QuantityRatio)
+		return ratio, detail.QuantityRatio != nil && ratio >= rule.Threshold
+	default:
+		return 0, false
+	}
+}
+
+func alertMessage(rule *entities.AlertRule, detail *spiders.StockWithDetail, value float64) string {
+	switch rule.Kind {
+	case entities.AlertPriceAbove:
+		return fmt.Sprintf("%s(%s) price %.3f is above %.3f", detail.Name, rule.Code, value, rule.Threshold)
+	case entities.AlertPriceBelow:
+		return fmt.Sprintf("%s(%s) price %.3f is below %.3f", detail.Name, rule.Code, value, rule.Threshold)
+	case entities.AlertGainAbove, entities.AlertGainBelow:
+		return fmt.Sprintf("%s(%s) gains %.2f%% crossed %.2f%%", detail.Name, rule.Code, value, rule.Threshold)
+	case entities.AlertLimitUp:
+		return fmt.Sprintf("%s(%s) touched limit up at %.3f", detail.Name, rule.Code, value)
+	case entities.AlertLimitDown:
+		return fmt.Sprintf("%s(%s) touched limit down at %.3f", detail.Name, rule.Code, value)
+	default:
+		return fmt.Sprintf("%s(%s) volume ratio %.2f is above %.2f", detail.Name, rule.Code, value, rule.Threshold)
+	}
+}
+
+// Evaluate polls the quotes of all enabled rules once. A rule fires when its condition
+// starts to hold and its cooldown since the last firing has passed.
+func (s *AlertImpl) Evaluate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rules, err := s.AlertStore.AlertRules()
+	if err != nil {
+		return err
+	}
+	details := make(map[string]*spiders.StockWithDetail)
+	now := s.now()
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		detail, ok := details[rule.Code]
+		if !ok {
+			detail, err = s.stock.Stock(rule.Code)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"code": rule.Code,
+				}).Error(err)
+			}
+			details[rule.Code] = detail
+		}
+		if detail == nil {
+			continue
+		}
+		value, hit := matchAlertRule(rule, detail)
+		cooled := now.Sub(rule.TriggeredAt) >= time.Duration(rule.Cooldown)*time.Second
+		fire := hit && !rule.Active && cooled
+		if hit == rule.Active && !fire {
+			continue
+		}
+		rule.Active = hit
+		if fire {
+			rule.TriggeredAt = now
+		}
+		if err := s.AlertStore.UpdateAlertRule(rule); err != nil {
+			return err
+		}
+		if fire {
+			event := &entities.AlertEvent{
+				RuleID:    rule.ID,
+				Code:      rule.Code,
+				Name:      detail.Name,
+				Kind:      rule.Kind,
+				Threshold: rule.Threshold,
+				Value:     value,
+				Message:   alertMessage(rule, detail, value),
+				Time:      now,
+			}
+			if err := s.Publish(event, rule.Notifiers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Publish records an event and hands it to the notifiers named, or all of them when
+// names is empty. Notifier failures are logged and do not fail the call.
+func (s *AlertImpl) Publish(event *entities.AlertEvent, names []string) error {
+	if event.Time.IsZero() {
+		event.Time = s.now()
+	}
+	if err := s.AlertStore.CreateAlertEvent(event); err != nil {
+		return err
+	}
+	for _, n := range s.notifiers {
+		if !notifierSelected(n.Name(), names) {
+			continue
+		}
+		if err := n.Notify(event); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"notifier": n.Name(),
+				"event":    event.ID,
+			}).Error(err)
+		}
+	}
+	return nil
+}
+
+func notifierSelected(name string, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run evaluates the rules every interval until stop is closed.
+func (s *AlertImpl) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Evaluate(); err != nil {
+				logrus.Error(err)
+			}
+		}
+	}
+}
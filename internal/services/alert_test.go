@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/internal/store"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// number makes a nullable quote field.
+func number(v float64) *float64 {
+	return &v
+}
+
+func openStore(t *testing.T) *store.Store {
+	dir, err := ioutil.TempDir("", "stock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	db, err := store.Open(filepath.Join(dir, "stock.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
+type recordingNotifier struct {
+	events []*entities.AlertEvent
+}
+
+func (n *recordingNotifier) Name() string {
+	return "record"
+}
+
+func (n *recordingNotifier) Notify(event *entities.AlertEvent) error {
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestAlertImpl_Evaluate(t *testing.T) {
+	db := openStore(t)
+	provider := &fakeProvider{detail: &spiders.StockWithDetail{
+		Stock:         spiders.Stock{Name: "山东高速", InternalCode: "1.600350"},
+		Price:         number(6.3),
+		LimitUp:       6.93,
+		LimitDown:     5.67,
+		QuantityRatio: number(1),
+	}}
+	notifier := new(recordingNotifier)
+	service := services.NewAlertService(db, services.NewService(provider, nil), notifier)
+
+	assert.Error(t, service.CreateAlertRule(&entities.AlertRule{Code: "1.600350", Kind: "unknown", Enabled: true}))
+	price := &entities.AlertRule{Code: "1.600350", Kind: entities.AlertPriceAbove, Threshold: 6.5, Enabled: true}
+	cooldown := &entities.AlertRule{Code: "1.600350", Kind: entities.AlertLimitUp, Cooldown: 3600, Enabled: true}
+	assert.NoError(t, service.CreateAlertRule(price))
+	assert.NoError(t, service.CreateAlertRule(cooldown))
+
+	steps := []struct {
+		price float64
+		fired int
+	}{
+		{6.3, 0},
+		{6.6, 1},  // price crosses 6.5
+		{6.7, 1},  // still above, no repeat
+		{6.93, 2}, // limit up
+		{6.4, 2},  // both conditions clear
+		{6.93, 3}, // price fires again, limit up is cooling down
+	}
+	for i, step := range steps {
+		provider.detail.Price = number(step.price)
+		if assert.NoError(t, service.Evaluate()) {
+			assert.Len(t, notifier.events, step.fired, "step %d", i)
+		}
+	}
+	assert.Equal(t, entities.AlertLimitUp, notifier.events[1].Kind)
+	events, err := service.AlertEvents(10)
+	if assert.NoError(t, err) && assert.Len(t, events, 3) {
+		assert.Equal(t, entities.AlertPriceAbove, events[0].Kind)
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"stock/pkg/analytics"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// DefaultBenchmark is the SSE Composite index.
+const DefaultBenchmark = "1.000001"
+
+// Compare relates the daily closes of codes to each other and to the benchmark over the
+// trading days all of them have in the range, adjusted for dividends and bonus shares.
+func (s *StockImpl) Compare(codes []string, benchmark string, start, end time.Time) (*analytics.Result, error) {
+	if benchmark == "" {
+		benchmark = DefaultBenchmark
+	}
+	series := make([][]*spiders.KLine, len(codes))
+	for i, code := range codes {
+		lines, err := s.adjustedKLines(code, spiders.OneDay, start, end)
+		if err != nil {
+			return nil, err
+		}
+		series[i] = lines
+	}
+	benchmarkLines, err := s.adjustedKLines(benchmark, spiders.OneDay, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return analytics.Compare(codes, series, benchmark, benchmarkLines)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"stock/pkg/backtest"
+	"stock/pkg/spiders"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Backtest runs strategy over the candles KLines returns for the range, adjusted for
+// dividends and bonus shares so that ex-dates do not show in the equity. The price limit
+// of the A-share rules is the one of the current quote, 5% for ST stocks and none for
+// indexes, or the one of the board when there is no quote.
+func (s *StockImpl) Backtest(code string, t spiders.Type, start, end time.Time, strategy backtest.Strategy, cfg backtest.Config) (*backtest.Report, error) {
+	if cfg.LimitRatio > 0 {
+		if detail, err := s.Stock(code); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"code": code,
+			}).Error(err)
+		} else {
+			cfg.LimitRatio = backtest.PriceLimitRatio(detail.LimitUp, detail.Close)
+		}
+	}
+	lines, err := s.adjustedKLines(code, t, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return backtest.Run(lines, strategy, cfg)
+}
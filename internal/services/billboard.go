@@ -0,0 +1,60 @@
+package services
+
+import (
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// Billboard returns the 龙虎榜 entries between start and end, of code only unless it is
+// empty, joined with the saved limit boards of their days. The seats are fetched for a
+// single code only, a whole day lists too many stocks for them.
+func (s *LimitBoardImpl) Billboard(code string, start, end time.Time) ([]*entities.BillboardEntry, error) {
+	provider, ok := s.stock.IStock.(spiders.BillboardProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	entries, err := provider.Billboard(code, start, end)
+	if err != nil {
+		return nil, err
+	}
+	loc := spiders.MarketOf(cnIndex).Location
+	dayOf := func(t time.Time) string {
+		return t.In(loc).Format("2006-01-02")
+	}
+	if code != "" {
+		// entries come by date, those of a day are next to each other
+		for i := 0; i < len(entries); {
+			j := i + 1
+			for j < len(entries) && dayOf(entries[j].Date) == dayOf(entries[i].Date) {
+				j++
+			}
+			seats, err := provider.BillboardSeats(code, entries[i].Date)
+			if err != nil {
+				return nil, err
+			}
+			spiders.AttachSeats(entries[i:j], seats)
+			i = j
+		}
+	}
+	boards, err := s.LimitBoardStore.LimitBoards(dayOf(start), dayOf(end))
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]*entities.LimitStock)
+	for _, b := range boards {
+		for _, list := range [][]*entities.LimitStock{b.Up, b.Down} {
+			for _, l := range list {
+				limits[b.Date+" "+l.Code] = l
+			}
+		}
+	}
+	list := make([]*entities.BillboardEntry, len(entries))
+	for i, e := range entries {
+		list[i] = &entities.BillboardEntry{
+			BillboardEntry: e,
+			Limit:          limits[dayOf(e.Date)+" "+e.Code],
+		}
+	}
+	return list, nil
+}
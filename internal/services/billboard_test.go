@@ -0,0 +1,41 @@
+package services_test
+
+import (
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitBoardImpl_Billboard(t *testing.T) {
+	loc := spiders.MarketOf("1.600350").Location
+	day := time.Date(2020, 11, 4, 0, 0, 0, 0, loc)
+	provider := &fakeProvider{billboard: []*spiders.BillboardEntry{
+		{Date: day, Code: "1.600350", Reason: "a"},
+		{Date: day, Code: "1.600350", Reason: "b"},
+		{Date: day.AddDate(0, 0, 1), Code: "1.600350", Reason: "a"},
+	}}
+	db := openStore(t)
+	assert.NoError(t, db.SaveLimitBoard(&entities.LimitBoard{
+		Date: "2020-11-04",
+		Up:   []*entities.LimitStock{{Code: "1.600350", Kind: entities.LimitUp, Consecutive: 2}},
+	}))
+	service := services.NewLimitBoardService(db, services.NewService(provider, nil))
+	list, err := service.Billboard("1.600350", day, day.AddDate(0, 0, 1))
+	if assert.NoError(t, err) && assert.Len(t, list, 3) {
+		assert.Len(t, provider.seatDays, 2)
+		assert.Len(t, list[1].Buyers, 1)
+		if assert.NotNil(t, list[0].Limit) {
+			assert.Equal(t, 2, list[0].Limit.Consecutive)
+		}
+		assert.Nil(t, list[2].Limit)
+	}
+
+	provider.seatDays = nil
+	_, err = service.Billboard("", day, day)
+	assert.NoError(t, err)
+	assert.Empty(t, provider.seatDays)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// ConnectFlow gathers the flows of direction: the intraday series, the days between
+// start and end and the top traded stocks of date, of the last day with the zero time.
+func (s *StockImpl) ConnectFlow(direction spiders.ConnectDirection, start, end, date time.Time) (*entities.ConnectFlow, error) {
+	provider, ok := s.IStock.(spiders.ConnectFlowProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	intraday, err := provider.ConnectIntraday(direction)
+	if err != nil {
+		return nil, err
+	}
+	history, err := provider.ConnectHistory(direction, start, end)
+	if err != nil {
+		return nil, err
+	}
+	top, err := provider.ConnectTopTraded(direction, date)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.ConnectFlow{
+		Direction: direction,
+		Intraday:  intraday,
+		History:   history,
+		Top:       top,
+	}, nil
+}
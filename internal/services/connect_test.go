@@ -0,0 +1,29 @@
+package services_test
+
+import (
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockImpl_ConnectFlow(t *testing.T) {
+	provider := new(fakeProvider)
+	start := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	service := services.NewService(provider, nil)
+	flow, err := service.ConnectFlow(spiders.Southbound, start, start.AddDate(0, 1, 0), time.Time{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, spiders.Southbound, flow.Direction)
+		assert.Equal(t, []spiders.ConnectDirection{spiders.Southbound, spiders.Southbound, spiders.Southbound}, provider.connect)
+		assert.True(t, provider.topDate.IsZero(), "the zero date asks for the last day")
+	}
+	_, err = service.ConnectFlow(spiders.Northbound, start, start, start)
+	if assert.NoError(t, err) {
+		assert.Equal(t, start, provider.topDate)
+	}
+
+	_, err = services.NewService(new(bareProvider), nil).ConnectFlow(spiders.Northbound, start, start, time.Time{})
+	assert.Equal(t, services.ErrUnsupportedProvider, err)
+}
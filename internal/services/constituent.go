@@ -0,0 +1,57 @@
+package services
+
+import (
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+)
+
+// quoteBatch is the most codes asked from MultiStock at once, to keep the URL short.
+const quoteBatch = 100
+
+// IndexConstituents returns the members of an index or the holdings of an ETF, heaviest
+// first as the provider lists them, each with its live quote.
+func (s *StockImpl) IndexConstituents(code string) ([]*entities.Constituent, error) {
+	provider, ok := s.IStock.(spiders.ConstituentProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	members, err := provider.IndexConstituents(code)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, len(members))
+	for i, m := range members {
+		codes[i] = m.Code
+	}
+	quotes, err := s.quotes(codes)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*entities.Constituent, len(members))
+	for i, m := range members {
+		list[i] = &entities.Constituent{
+			Constituent: m,
+			Quote:       quotes[m.Code],
+		}
+	}
+	return list, nil
+}
+
+// quotes returns the MultiStock quotes of codes by secid, asked by batches of quoteBatch.
+func (s *StockImpl) quotes(codes []string) (map[string]*spiders.MultiStock, error) {
+	quotes := make(map[string]*spiders.MultiStock, len(codes))
+	for i := 0; i < len(codes); i += quoteBatch {
+		batch := codes[i:]
+		if len(batch) > quoteBatch {
+			batch = batch[:quoteBatch]
+		}
+		stocks, err := s.MultiStock(batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, stock := range stocks {
+			quotes[stock.InternalCode] = stock
+		}
+	}
+	return quotes, nil
+}
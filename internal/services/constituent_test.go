@@ -0,0 +1,31 @@
+package services_test
+
+import (
+	"fmt"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockImpl_IndexConstituents(t *testing.T) {
+	provider := &fakeProvider{quotes: map[string]*spiders.MultiStock{}}
+	for i := 0; i < 150; i++ {
+		code := fmt.Sprintf("1.%06d", 600000+i)
+		provider.members = append(provider.members, &spiders.Constituent{Code: code, Weight: 1})
+		if i != 2 { // delisted
+			provider.quotes[code] = &spiders.MultiStock{Stock: spiders.Stock{InternalCode: code}, Price: number(10)}
+		}
+	}
+	list, err := services.NewService(provider, nil).IndexConstituents("1.000300")
+	if assert.NoError(t, err) && assert.Len(t, list, 150) {
+		if assert.Len(t, provider.quoted, 2) {
+			assert.Len(t, provider.quoted[0], 100)
+			assert.Len(t, provider.quoted[1], 50)
+		}
+		assert.Equal(t, "1.600000", list[0].Code)
+		assert.Equal(t, "1.600000", list[0].Quote.InternalCode)
+		assert.Nil(t, list[2].Quote)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+)
+
+func (s *StockImpl) convertibleProvider() (spiders.ConvertibleProvider, error) {
+	provider, ok := s.IStock.(spiders.ConvertibleProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	return provider, nil
+}
+
+// ConvertibleBonds lists the listed convertible bonds, each joined with the quote of its
+// underlying stock.
+func (s *StockImpl) ConvertibleBonds() ([]*entities.ConvertibleBond, error) {
+	provider, err := s.convertibleProvider()
+	if err != nil {
+		return nil, err
+	}
+	bonds, err := provider.ConvertibleBonds()
+	if err != nil {
+		return nil, err
+	}
+	return s.joinUnderlying(bonds)
+}
+
+// ConvertibleBond returns a convertible bond by its secid with the quote of its underlying stock.
+func (s *StockImpl) ConvertibleBond(code string) (*entities.ConvertibleBond, error) {
+	provider, err := s.convertibleProvider()
+	if err != nil {
+		return nil, err
+	}
+	bond, err := provider.ConvertibleBond(code)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.joinUnderlying([]*spiders.ConvertibleBond{bond})
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func (s *StockImpl) joinUnderlying(bonds []*spiders.ConvertibleBond) ([]*entities.ConvertibleBond, error) {
+	codes := make([]string, 0, len(bonds))
+	seen := make(map[string]bool, len(bonds))
+	for _, b := range bonds {
+		if b.StockCode != "" && !seen[b.StockCode] {
+			seen[b.StockCode] = true
+			codes = append(codes, b.StockCode)
+		}
+	}
+	quotes, err := s.quotes(codes)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*entities.ConvertibleBond, len(bonds))
+	for i, b := range bonds {
+		list[i] = &entities.ConvertibleBond{
+			ConvertibleBond: b,
+			Stock:           quotes[b.StockCode],
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,29 @@
+package services_test
+
+import (
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockImpl_ConvertibleBonds(t *testing.T) {
+	provider := &fakeProvider{
+		bonds: []*spiders.ConvertibleBond{
+			{Code: "1.113050", StockCode: "1.601009"},
+			{Code: "1.110053", StockCode: "1.601009"},
+			{Code: "0.128999"},
+		},
+		quotes: map[string]*spiders.MultiStock{"1.601009": {Stock: spiders.Stock{InternalCode: "1.601009"}, Price: number(10)}},
+	}
+	list, err := services.NewService(provider, nil).ConvertibleBonds()
+	if assert.NoError(t, err) && assert.Len(t, list, 3) {
+		assert.Equal(t, [][]string{{"1.601009"}}, provider.quoted)
+		assert.Equal(t, "1.601009", list[1].Stock.InternalCode)
+		assert.Nil(t, list[2].Stock)
+	}
+
+	_, err = services.NewService(new(bareProvider), nil).ConvertibleBonds()
+	assert.Equal(t, services.ErrUnsupportedProvider, err)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"stock/internal/entities"
+	"stock/pkg/adjust"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// CorporateActions returns the dividend plans and rights issues of code. withFactors also
+// computes the factor of each executed one from the unadjusted close before its ex-date.
+func (s *StockImpl) CorporateActions(code string, withFactors bool) (*entities.CorporateActions, error) {
+	provider, ok := s.IStock.(spiders.CorporateActionProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	actions, err := provider.CorporateActions(code)
+	if err != nil {
+		return nil, err
+	}
+	result := &entities.CorporateActions{
+		Code:    code,
+		Actions: actions,
+	}
+	if !withFactors {
+		return result, nil
+	}
+	result.Factors = make([]*adjust.Event, 0)
+	var first, last time.Time
+	for _, a := range actions {
+		if a.ExDate.IsZero() {
+			continue
+		}
+		if first.IsZero() || a.ExDate.Before(first) {
+			first = a.ExDate
+		}
+		if a.ExDate.After(last) {
+			last = a.ExDate
+		}
+	}
+	if first.IsZero() {
+		return result, nil
+	}
+	// a margin for the holidays before the first ex-date
+	lines, err := s.KLines(code, spiders.OneDay, first.AddDate(0, 0, -15), last)
+	if err != nil {
+		return nil, err
+	}
+	result.Factors = adjust.Events(lines, actions)
+	return result, nil
+}
+
+// adjustedKLines returns KLines adjusted to the latest prices with the factors of the
+// corporate actions within them, so that ex-dates are not price drops. Lines of markets
+// without corporate actions, or of providers without them, are returned unadjusted.
+func (s *StockImpl) adjustedKLines(code string, t spiders.Type, start, end time.Time) ([]*spiders.KLine, error) {
+	lines, err := s.KLines(code, t, start, end)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := s.IStock.(spiders.CorporateActionProvider)
+	if !ok || len(lines) == 0 {
+		return lines, nil
+	}
+	actions, err := provider.CorporateActions(code)
+	if errors.Is(err, spiders.ErrUnsupportedMarket) {
+		return lines, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	events := adjust.Events(lines, actions)
+	if len(events) == 0 {
+		return lines, nil
+	}
+	return adjust.Forward(lines, events), nil
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockImpl_CorporateActions(t *testing.T) {
+	day := time.Date(2020, 6, 12, 0, 0, 0, 0, spiders.MarketOf("0.300059").Location)
+	provider := &fakeProvider{
+		lines: []*spiders.KLine{
+			{Close: 10.5, Time: day.AddDate(0, 0, -1)},
+			{Close: 7.5, Time: day},
+		},
+		actions: []*spiders.CorporateAction{
+			{ExDate: day, CashDividend: 0.07, TransferShares: 0.4},
+			{Plan: "10派1元", CashDividend: 0.1},
+		},
+	}
+	service := services.NewService(provider, nil)
+
+	data, err := service.CorporateActions("0.300059", false)
+	if assert.NoError(t, err) {
+		assert.Len(t, data.Actions, 2)
+		assert.Nil(t, data.Factors)
+	}
+	data, err = service.CorporateActions("0.300059", true)
+	if assert.NoError(t, err) && assert.Len(t, data.Factors, 1) {
+		assert.InDelta(t, 10.5/7.45, data.Factors[0].Factor, 1e-9)
+		assert.Equal(t, []spiders.Type{spiders.OneDay}, provider.requested)
+	}
+}
+
+func TestStockImpl_CompareAdjusted(t *testing.T) {
+	day := time.Date(2020, 6, 12, 0, 0, 0, 0, spiders.MarketOf("0.300059").Location)
+	provider := &fakeProvider{
+		lines: []*spiders.KLine{
+			{Close: 10, Time: day.AddDate(0, 0, -2)},
+			{Close: 10.5, Time: day.AddDate(0, 0, -1)},
+			{Close: 7.5, Time: day},
+		},
+		actions: []*spiders.CorporateAction{{ExDate: day, CashDividend: 0.07, TransferShares: 0.4}},
+	}
+	service := services.NewService(provider, nil)
+
+	result, err := service.Compare([]string{"0.300059"}, "0.300059", day.AddDate(0, 0, -2), day)
+	if assert.NoError(t, err) && assert.Len(t, result.Series, 1) {
+		// the ex-date is a rise from the 7.45 reference price, not a 29% drop
+		assert.InDelta(t, 7.5/7.45-1, result.Series[0].Returns[len(result.Series[0].Returns)-1], 1e-9)
+		assert.InDelta(t, 1, result.Series[0].Beta, 1e-9)
+	}
+}
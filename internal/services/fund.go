@@ -0,0 +1,32 @@
+package services
+
+import (
+	"stock/pkg/spiders"
+	"time"
+)
+
+func (s *StockImpl) fundProvider() (spiders.FundProvider, error) {
+	provider, ok := s.IStock.(spiders.FundProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	return provider, nil
+}
+
+// FundNAV returns the daily values of a fund, code being a fund code or the
+// internal_code of a fund found by Search.
+func (s *StockImpl) FundNAV(code string, start, end time.Time) ([]*spiders.FundNAV, error) {
+	provider, err := s.fundProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.FundNAV(code, start, end)
+}
+
+func (s *StockImpl) FundEstimate(code string) (*spiders.FundEstimate, error) {
+	provider, err := s.fundProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.FundEstimate(code)
+}
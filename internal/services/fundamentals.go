@@ -0,0 +1,12 @@
+package services
+
+import "stock/pkg/spiders"
+
+// Fundamentals returns the last periods financial reports of code.
+func (s *StockImpl) Fundamentals(code string, periods int) (*spiders.Fundamentals, error) {
+	provider, ok := s.IStock.(spiders.FundamentalsProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	return provider.Fundamentals(code, periods)
+}
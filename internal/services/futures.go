@@ -0,0 +1,30 @@
+package services
+
+import "stock/pkg/spiders"
+
+func (s *StockImpl) futuresProvider() (spiders.FuturesProvider, error) {
+	provider, ok := s.IStock.(spiders.FuturesProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	return provider, nil
+}
+
+// FuturesContracts lists the contracts of a futures product in delivery order.
+func (s *StockImpl) FuturesContracts(product string) ([]*spiders.FuturesContract, error) {
+	provider, err := s.futuresProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.FuturesContracts(product)
+}
+
+// MainContract resolves the main contract of a futures product, its secid is what the
+// KLine and Trend of the product are asked with.
+func (s *StockImpl) MainContract(product string) (*spiders.FuturesContract, error) {
+	provider, err := s.futuresProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.MainContract(product)
+}
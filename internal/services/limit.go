@@ -0,0 +1,259 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"stock/internal/entities"
+	"stock/pkg/backtest"
+	"stock/pkg/spiders"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LimitBoardStore persists the daily limit boards, see store.Store.
+type LimitBoardStore interface {
+	SaveLimitBoard(b *entities.LimitBoard) error
+	LimitBoard(date string) (*entities.LimitBoard, error)
+	LatestLimitBoard() (*entities.LimitBoard, error)
+	LimitBoards(from, to string) ([]*entities.LimitBoard, error)
+}
+
+// limitEpsilon absorbs the float error of scaled prices compared with limit prices.
+const limitEpsilon = 0.0005
+
+// consecutiveLookback is the calendar days of daily candles searched for a limit up streak.
+const consecutiveLookback = 60
+
+// cnIndex is the code whose sessions decide when the A-share market is open.
+const cnIndex = "1.000001"
+
+// LimitBoardImpl scans the A-share market for the stocks that reached their price limits.
+type LimitBoardImpl struct {
+	LimitBoardStore
+	stock *StockImpl
+	mu    sync.Mutex
+	// Now is the clock of the tracker, replaced in tests.
+	Now func() time.Time
+}
+
+func NewLimitBoardService(store LimitBoardStore, stock *StockImpl) *LimitBoardImpl {
+	return &LimitBoardImpl{
+		LimitBoardStore: store,
+		stock:           stock,
+		Now:             time.Now,
+	}
+}
+
+// limitKind returns the limit the quote reached today, preferring the one it is at.
+func limitKind(q *spiders.MultiStock) entities.LimitKind {
+	price, high, low := spiders.Value(q.Price), spiders.Value(q.High), spiders.Value(q.Low)
+	if price <= 0 {
+		return ""
+	}
+	atDown := q.LimitDown > 0 && price <= q.LimitDown+limitEpsilon
+	if q.LimitUp > 0 && high >= q.LimitUp-limitEpsilon && !atDown {
+		return entities.LimitUp
+	}
+	if q.LimitDown > 0 && low > 0 && low <= q.LimitDown+limitEpsilon {
+		return entities.LimitDown
+	}
+	return ""
+}
+
+func atLimit(kind entities.LimitKind, price, limit float64) bool {
+	if kind == entities.LimitUp {
+		return price >= limit-limitEpsilon
+	}
+	return price > 0 && price <= limit+limitEpsilon
+}
+
+// touches finds in minute trends when the price first reached the limit, when it last
+// came back to it and the minutes it left it, at the resolution of the trend.
+func touches(trends []*spiders.Trend, kind entities.LimitKind, limit float64) (first, last time.Time, opens []time.Time) {
+	opens = make([]time.Time, 0)
+	at := false
+	for _, t := range trends {
+		hit := atLimit(kind, t.Price, limit)
+		switch {
+		case hit && !at:
+			if first.IsZero() {
+				first = t.Time
+			}
+			last = t.Time
+		case !hit && at:
+			opens = append(opens, t.Time)
+		}
+		at = hit
+	}
+	return first, last, opens
+}
+
+// limitStreak counts the limit up closes in a row at the end of daily candles.
+func limitStreak(lines []*spiders.KLine, ratio float64) int {
+	n := 0
+	for i := len(lines) - 1; i > 0; i-- {
+		limit := math.Round(lines[i-1].Close*(1+ratio)*100) / 100
+		if lines[i].Close < limit-limitEpsilon {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// consecutive returns the limit up closes in a row before day, plus today when sealed.
+func (s *LimitBoardImpl) consecutive(q *spiders.MultiStock, sealed bool, day time.Time) (int, error) {
+	n := 0
+	if sealed {
+		n = 1
+	}
+	if q.Close <= 0 {
+		return n, nil
+	}
+	ratio := backtest.PriceLimitRatio(q.LimitUp, q.Close)
+	end := day.Add(-time.Second)
+	lines, err := s.stock.KLines(q.InternalCode, spiders.OneDay, day.AddDate(0, 0, -consecutiveLookback), end)
+	if err != nil {
+		return n, err
+	}
+	for len(lines) > 0 && !lines[len(lines)-1].Time.Before(day) {
+		lines = lines[:len(lines)-1]
+	}
+	return n + limitStreak(lines, ratio), nil
+}
+
+// track builds the entry of a stock at its limit on day, the trading day of the quotes.
+func (s *LimitBoardImpl) track(q *spiders.MultiStock, kind entities.LimitKind, day time.Time) *entities.LimitStock {
+	limit := q.LimitUp
+	if kind == entities.LimitDown {
+		limit = q.LimitDown
+	}
+	l := &entities.LimitStock{
+		Code:           q.InternalCode,
+		Name:           q.Name,
+		Kind:           kind,
+		Price:          spiders.Value(q.Price),
+		LimitPrice:     limit,
+		Gains:          spiders.Value(q.Gains),
+		TurnoverAmount: spiders.Value(q.TurnoverAmount),
+		TurnoverRate:   spiders.Value(q.TurnoverRate),
+		Circulation:    q.Circulation,
+		Sealed:         atLimit(kind, spiders.Value(q.Price), limit),
+		Opens:          make([]time.Time, 0),
+	}
+	fields := logrus.Fields{
+		"code": q.InternalCode,
+	}
+	if trends, err := s.stock.Trend(q.InternalCode, 1, false); err != nil {
+		logrus.WithFields(fields).Error(err)
+	} else {
+		l.FirstTouch, l.LastTouch, l.Opens = touches(trends, kind, limit)
+	}
+	if kind == entities.LimitUp {
+		n, err := s.consecutive(q, l.Sealed, day)
+		if err != nil {
+			logrus.WithFields(fields).Error(err)
+		}
+		l.Consecutive = n
+	}
+	return l
+}
+
+// tradingDay returns the day the quotes are of, the trading day of the last minute of the
+// index, which is the previous session on weekends and holidays.
+func (s *LimitBoardImpl) tradingDay() (time.Time, error) {
+	trends, err := s.stock.Trend(cnIndex, 1, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(trends) == 0 {
+		return time.Time{}, fmt.Errorf("no trend of [%s]", cnIndex)
+	}
+	return spiders.MarketOf(cnIndex).TradingDay(trends[len(trends)-1].Time), nil
+}
+
+// Refresh scans the market and saves the board of the trading day of the quotes.
+func (s *LimitBoardImpl) Refresh() (*entities.LimitBoard, error) {
+	scanner, ok := s.stock.IStock.(spiders.Scanner)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	day, err := s.tradingDay()
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := scanner.Scan(spiders.ScopeCN)
+	if err != nil {
+		return nil, err
+	}
+	board := &entities.LimitBoard{
+		Date:      day.Format("2006-01-02"),
+		UpdatedAt: s.Now().In(day.Location()),
+		Up:        make([]*entities.LimitStock, 0),
+		Down:      make([]*entities.LimitStock, 0),
+	}
+	for _, q := range quotes {
+		switch kind := limitKind(q); kind {
+		case entities.LimitUp:
+			board.Up = append(board.Up, s.track(q, kind, day))
+		case entities.LimitDown:
+			board.Down = append(board.Down, s.track(q, kind, day))
+		}
+	}
+	// highest streak first, then earliest to reach the limit
+	sort.SliceStable(board.Up, func(i, j int) bool {
+		a, b := board.Up[i], board.Up[j]
+		if a.Consecutive != b.Consecutive {
+			return a.Consecutive > b.Consecutive
+		}
+		return a.FirstTouch.Before(b.FirstTouch)
+	})
+	sort.SliceStable(board.Down, func(i, j int) bool {
+		return board.Down[i].FirstTouch.Before(board.Down[j].FirstTouch)
+	})
+	if err := s.LimitBoardStore.SaveLimitBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// Board returns the saved board of date, or the latest one when date is empty. Boards
+// are scanned by Run only.
+func (s *LimitBoardImpl) Board(date string) (*entities.LimitBoard, error) {
+	if date == "" {
+		return s.LimitBoardStore.LatestLimitBoard()
+	}
+	return s.LimitBoardStore.LimitBoard(date)
+}
+
+// Run refreshes the board at once, for the last session when started after the close,
+// then every interval while the market is open and once more after it closes, until stop
+// is closed.
+func (s *LimitBoardImpl) Run(interval time.Duration, stop <-chan struct{}) {
+	if _, err := s.Refresh(); err != nil {
+		logrus.Error(err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	open := false
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			wasOpen := open
+			open = tradable(cnIndex, s.Now())
+			if !open && !wasOpen {
+				continue
+			}
+			if _, err := s.Refresh(); err != nil {
+				logrus.Error(err)
+			}
+		}
+	}
+}
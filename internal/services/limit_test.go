@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitBoardImpl_Refresh(t *testing.T) {
+	loc := spiders.MarketOf("1.600350").Location
+	day := time.Date(2020, 11, 4, 0, 0, 0, 0, loc)
+	minute := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	quote := func(code string, price, high, low, close float64) *spiders.MultiStock {
+		return &spiders.MultiStock{
+			Stock: spiders.Stock{InternalCode: code},
+			Price: number(price), High: number(high), Low: number(low), Close: close,
+			LimitUp:   float64(int(close*110+0.5)) / 100,
+			LimitDown: float64(int(close*90+0.5)) / 100,
+		}
+	}
+	provider := &fakeProvider{
+		scanned: []*spiders.MultiStock{
+			quote("1.600350", 6.93, 6.93, 6.4, 6.3),          // sealed, second board in a row
+			quote("0.000001", 10.5, 11, 10, 10),              // touched and broke
+			quote("1.600000", 9, 9.5, 9, 10),                 // limit down
+			quote("1.601398", 5.1, 5.2, 5, 5),                // nothing
+			{Stock: spiders.Stock{InternalCode: "0.300999"}}, // suspended
+		},
+		trends: map[string][]*spiders.Trend{
+			"1.000001": {
+				{Time: minute(9, 31), Price: 3300},
+				{Time: minute(15, 0), Price: 3310},
+			},
+			"1.600350": {
+				{Time: minute(9, 31), Price: 6.5},
+				{Time: minute(9, 45), Price: 6.93},
+				{Time: minute(10, 0), Price: 6.9},
+				{Time: minute(10, 30), Price: 6.93},
+			},
+			"0.000001": {
+				{Time: minute(13, 5), Price: 11},
+				{Time: minute(13, 6), Price: 10.8},
+			},
+		},
+		linesOf: map[string][]*spiders.KLine{
+			"1.600350": {
+				{Close: 5, Time: day.AddDate(0, 0, -3)},
+				{Close: 5.21, Time: day.AddDate(0, 0, -2)},
+				{Close: 5.73, Time: day.AddDate(0, 0, -1)}, // 5.21 * 1.1
+				{Close: 6.3, Time: day},                    // today, ignored
+			},
+		},
+	}
+	service := services.NewLimitBoardService(openStore(t), services.NewService(provider, nil))
+	// the board is of the day of the quotes, not of the clock
+	service.Now = func() time.Time { return minute(15, 1).AddDate(0, 0, 3) }
+
+	board, err := service.Refresh()
+	if !assert.NoError(t, err) || !assert.Len(t, board.Up, 2) || !assert.Len(t, board.Down, 1) {
+		return
+	}
+	assert.Equal(t, "2020-11-04", board.Date)
+	sealed := board.Up[0]
+	assert.Equal(t, "1.600350", sealed.Code)
+	assert.True(t, sealed.Sealed)
+	assert.Equal(t, 2, sealed.Consecutive)
+	assert.Equal(t, minute(9, 45), sealed.FirstTouch)
+	assert.Equal(t, minute(10, 30), sealed.LastTouch)
+	assert.Equal(t, []time.Time{minute(10, 0)}, sealed.Opens)
+
+	broken := board.Up[1]
+	assert.False(t, broken.Sealed)
+	assert.Equal(t, 0, broken.Consecutive)
+	assert.Equal(t, []time.Time{minute(13, 6)}, broken.Opens)
+
+	assert.Equal(t, entities.LimitDown, board.Down[0].Kind)
+	assert.True(t, board.Down[0].Sealed)
+
+	saved, err := service.Board("2020-11-04")
+	if assert.NoError(t, err) {
+		assert.Len(t, saved.Up, 2)
+	}
+	_, err = service.Board("2020-11-03")
+	assert.Error(t, err)
+	latest, err := service.Board("")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2020-11-04", latest.Date)
+	}
+}
+
+func TestLimitBoardImpl_RunAfterClose(t *testing.T) {
+	loc := spiders.MarketOf("1.000001").Location
+	day := time.Date(2020, 11, 4, 0, 0, 0, 0, loc)
+	provider := &fakeProvider{
+		trends: map[string][]*spiders.Trend{
+			"1.000001": {{Time: day.Add(15 * time.Hour), Price: 3310}},
+		},
+	}
+	service := services.NewLimitBoardService(openStore(t), services.NewService(provider, nil))
+	service.Now = func() time.Time { return day.Add(20 * time.Hour) }
+	_, err := service.Board("")
+	assert.Error(t, err)
+
+	// started after the close, the board of the day is built before the first tick
+	stop := make(chan struct{})
+	close(stop)
+	service.Run(time.Hour, stop)
+	board, err := service.Board("")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2020-11-04", board.Date)
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// Margin returns the margin trading of code, or of the market with an empty code, aligned
+// on the daily candles of the stock, or of the Shanghai composite for the market.
+func (s *StockImpl) Margin(code string, start, end time.Time) (*entities.MarginSeries, error) {
+	provider, ok := s.IStock.(spiders.MarginProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	var (
+		margins []*spiders.Margin
+		err     error
+	)
+	candles := code
+	if code == "" {
+		candles = cnIndex
+		margins, err = provider.MarketMargin(start, end)
+	} else {
+		margins, err = provider.Margin(code, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.KLines(candles, spiders.OneDay, start, end)
+	if err != nil {
+		return nil, err
+	}
+	loc := spiders.MarketOf(candles).Location
+	byDay := make(map[string]*spiders.Margin, len(margins))
+	for _, m := range margins {
+		byDay[m.Date.In(loc).Format("2006-01-02")] = m
+	}
+	series := &entities.MarginSeries{
+		Code:   code,
+		Labels: make([]string, len(lines)),
+		Margin: make([]*spiders.Margin, len(lines)),
+	}
+	for i, line := range lines {
+		series.Labels[i] = line.Time.Format("2006-01-02")
+		series.Margin[i] = byDay[line.Time.In(loc).Format("2006-01-02")]
+	}
+	return series, nil
+}
@@ -0,0 +1,43 @@
+package services_test
+
+import (
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockImpl_Margin(t *testing.T) {
+	loc := spiders.MarketOf("0.300059").Location
+	day := func(d int) time.Time {
+		return time.Date(2020, 11, d, 0, 0, 0, 0, loc)
+	}
+	provider := &fakeProvider{
+		lines: []*spiders.KLine{
+			{Time: day(2), Type: spiders.OneDay},
+			{Time: day(3), Type: spiders.OneDay},
+			{Time: day(4), Type: spiders.OneDay},
+		},
+		// published in UTC, missing the 3rd
+		margins: []*spiders.Margin{
+			{Date: day(2).UTC(), FinancingBalance: number(1)},
+			{Date: day(4).UTC(), FinancingBalance: number(3)},
+		},
+	}
+	service := services.NewService(provider, nil)
+	series, err := service.Margin("0.300059", day(2), day(4))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"2020-11-02", "2020-11-03", "2020-11-04"}, series.Labels)
+		assert.Equal(t, float64(1), *series.Margin[0].FinancingBalance)
+		assert.Nil(t, series.Margin[1])
+		assert.Equal(t, float64(3), *series.Margin[2].FinancingBalance)
+		assert.Equal(t, []string{"0.300059"}, provider.candles)
+	}
+	series, err = service.Margin("", day(2), day(4))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "", series.Code)
+		assert.Equal(t, []string{"0.300059", "1.000001"}, provider.candles)
+	}
+}
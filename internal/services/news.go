@@ -0,0 +1,180 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pollSize is the number of latest announcements compared with the seen ones per poll.
+const pollSize = 20
+
+// seenSize is the number of announcement IDs remembered per code, more than a poll so that
+// a short answer does not make older announcements look new.
+const seenSize = 5 * pollSize
+
+// NewsStore remembers the announcements already surfaced, see store.Store.
+type NewsStore interface {
+	SeenAnnouncements(code string) ([]string, bool, error)
+	SaveSeenAnnouncements(code string, ids []string) error
+}
+
+type NewsImpl struct {
+	NewsStore
+	stock  *StockImpl
+	alerts *AlertImpl
+	mu     sync.Mutex
+}
+
+func NewNewsService(store NewsStore, stock *StockImpl, alerts *AlertImpl) *NewsImpl {
+	return &NewsImpl{
+		NewsStore: store,
+		stock:     stock,
+		alerts:    alerts,
+	}
+}
+
+func (s *NewsImpl) provider() (spiders.NewsProvider, error) {
+	provider, ok := s.stock.IStock.(spiders.NewsProvider)
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	return provider, nil
+}
+
+// News returns a page, counted from 1, of the articles or announcements of code.
+func (s *NewsImpl) News(code string, kind spiders.NewsKind, page, size int) ([]*spiders.News, error) {
+	provider, err := s.provider()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case spiders.NewsAnnouncement:
+		return provider.Announcements(code, page, size)
+	case spiders.NewsArticle, "":
+		return provider.News(code, page, size)
+	default:
+		return nil, fmt.Errorf("unknown news kind [%s]", kind)
+	}
+}
+
+// Poll publishes an announcement alert for each announcement of codes not seen at the
+// previous poll. The first poll of a code only records what is already there.
+func (s *NewsImpl) Poll(codes []string) error {
+	provider, err := s.provider()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, code := range codes {
+		list, err := provider.Announcements(code, 1, pollSize)
+		if errors.Is(err, spiders.ErrUnsupportedMarket) {
+			continue
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"code": code,
+			}).Error(err)
+			continue
+		}
+		if len(list) == 0 {
+			// most likely a hiccup of the API, keep what was seen
+			continue
+		}
+		seen, polled, err := s.NewsStore.SeenAnnouncements(code)
+		if err != nil {
+			return err
+		}
+		if polled {
+			if err := s.publish(code, list, seen); err != nil {
+				return err
+			}
+		}
+		if err := s.NewsStore.SaveSeenAnnouncements(code, mergeSeen(list, seen)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSeen returns the IDs of list, latest first, followed by the ones seen before, up to
+// seenSize.
+func mergeSeen(list []*spiders.News, seen []string) []string {
+	ids := make([]string, 0, len(list)+len(seen))
+	known := make(map[string]bool, len(list)+len(seen))
+	for _, n := range list {
+		if !known[n.ID] {
+			known[n.ID] = true
+			ids = append(ids, n.ID)
+		}
+	}
+	for _, id := range seen {
+		if !known[id] {
+			known[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > seenSize {
+		ids = ids[:seenSize]
+	}
+	return ids
+}
+
+func (s *NewsImpl) publish(code string, list []*spiders.News, seen []string) error {
+	known := make(map[string]bool, len(seen))
+	for _, id := range seen {
+		known[id] = true
+	}
+	name := ""
+	// the list is latest first, alert in the order they were filed
+	for i := len(list) - 1; i >= 0; i-- {
+		n := list[i]
+		if known[n.ID] {
+			continue
+		}
+		if name == "" {
+			if detail, err := s.stock.Stock(code); err == nil {
+				name = detail.Name
+			}
+		}
+		event := &entities.AlertEvent{
+			Code:    code,
+			Name:    name,
+			Kind:    entities.AlertAnnouncement,
+			Message: n.Title,
+			URL:     n.URL,
+			Time:    n.Time,
+		}
+		if err := s.alerts.Publish(event, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run polls the announcements of the codes returned by codes every interval until stop
+// is closed.
+func (s *NewsImpl) Run(interval time.Duration, codes func() ([]string, error), stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			list, err := codes()
+			if err == nil {
+				err = s.Poll(list)
+			}
+			if err != nil {
+				logrus.Error(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewsImpl_Poll(t *testing.T) {
+	db := openStore(t)
+	provider := &fakeProvider{
+		detail:  &spiders.StockWithDetail{Stock: spiders.Stock{Name: "东方财富"}},
+		notices: []*spiders.News{{ID: "AN1", Title: "first"}},
+	}
+	stock := services.NewService(provider, nil)
+	notifier := new(recordingNotifier)
+	service := services.NewNewsService(db, stock, services.NewAlertService(db, stock, notifier))
+	codes := []string{"0.300059", "116.00700"}
+
+	assert.NoError(t, service.Poll(codes))
+	assert.Empty(t, notifier.events, "the first poll only records")
+
+	provider.notices = []*spiders.News{
+		{ID: "AN3", Title: "third", URL: "https://example.com/AN3"},
+		{ID: "AN2", Title: "second"},
+		{ID: "AN1", Title: "first"},
+	}
+	assert.NoError(t, service.Poll(codes))
+	if assert.Len(t, notifier.events, 2) {
+		assert.Equal(t, "second", notifier.events[0].Message)
+		assert.Equal(t, "third", notifier.events[1].Message)
+		assert.Equal(t, entities.AlertAnnouncement, notifier.events[1].Kind)
+		assert.Equal(t, "https://example.com/AN3", notifier.events[1].URL)
+		assert.Equal(t, "东方财富", notifier.events[1].Name)
+	}
+
+	assert.NoError(t, service.Poll(codes))
+	assert.Len(t, notifier.events, 2)
+
+	// an empty then short answer does not bring the older ones back
+	provider.notices = nil
+	assert.NoError(t, service.Poll(codes))
+	provider.notices = []*spiders.News{{ID: "AN3", Title: "third"}}
+	assert.NoError(t, service.Poll(codes))
+	provider.notices = []*spiders.News{
+		{ID: "AN3", Title: "third"},
+		{ID: "AN2", Title: "second"},
+		{ID: "AN1", Title: "first"},
+	}
+	assert.NoError(t, service.Poll(codes))
+	assert.Len(t, notifier.events, 2)
+}
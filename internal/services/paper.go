@@ -0,0 +1,396 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"stock/internal/entities"
+	"stock/pkg/backtest"
+	"stock/pkg/spiders"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidOrder    = errors.New("invalid order")
+	ErrOrderNotPending = errors.New("order is not pending")
+)
+
+// PaperStore persists simulated accounts, see store.Store.
+type PaperStore interface {
+	CreatePaperAccount(a *entities.PaperAccount) error
+	PaperAccounts() ([]*entities.PaperAccount, error)
+	PaperAccount(id uint64) (*entities.PaperAccount, error)
+	SavePaperTrade(a *entities.PaperAccount, o *entities.PaperOrder, fill *entities.PaperFill) error
+	PaperOrder(accountID, id uint64) (*entities.PaperOrder, error)
+	PaperOrders(accountID uint64) ([]*entities.PaperOrder, error)
+	PaperFills(accountID uint64) ([]*entities.PaperFill, error)
+}
+
+// PaperImpl simulates order matching against live quotes with the trading rules of
+// backtest.DefaultConfig: lots, fees, T+1 and price limits.
+type PaperImpl struct {
+	PaperStore
+	stock *StockImpl
+	mu    sync.Mutex
+	// Now is the clock of the simulator, replaced in tests.
+	Now func() time.Time
+}
+
+func NewPaperService(store PaperStore, stock *StockImpl) *PaperImpl {
+	return &PaperImpl{
+		PaperStore: store,
+		stock:      stock,
+		Now:        time.Now,
+	}
+}
+
+func (s *PaperImpl) CreatePaperAccount(name string, cash float64) (*entities.PaperAccount, error) {
+	if cash <= 0 {
+		return nil, fmt.Errorf("%w: cash must be positive", ErrInvalidOrder)
+	}
+	a := &entities.PaperAccount{
+		Name:        name,
+		InitialCash: cash,
+		Cash:        cash,
+		Positions:   make(map[string]*entities.PaperPosition),
+	}
+	if err := s.PaperStore.CreatePaperAccount(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// tradable reports whether the market of code is in its regular or night session at now,
+// the breaks between sessions excluded.
+func tradable(code string, now time.Time) bool {
+	market := spiders.MarketOf(code)
+	local := now.In(market.Location)
+	if !market.InSession(local) {
+		return false
+	}
+	if market.SessionOf(local) == spiders.SessionNight {
+		// the night of Friday runs into Saturday, weekdays count from its open
+		local = local.Add(-time.Duration(market.Night.Open) * time.Minute)
+	}
+	return local.Weekday() != time.Saturday && local.Weekday() != time.Sunday
+}
+
+// expiry returns the close of the trading day an order placed at created is valid for,
+// the next trading day when it was placed after the close.
+func expiry(code string, created time.Time) time.Time {
+	market := spiders.MarketOf(code)
+	closing := time.Duration(market.Sessions[len(market.Sessions)-1].Close) * time.Minute
+	day := market.TradingDay(created)
+	for {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday && created.Before(day.Add(closing)) {
+			return day.Add(closing)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// sellable returns the shares of the position free to sell at now.
+func sellable(p *entities.PaperPosition, now time.Time, cfg backtest.Config) int64 {
+	if p == nil {
+		return 0
+	}
+	n := p.Quantity - p.Frozen
+	if cfg.T1 && sameDay(p.BoughtOn, now.In(p.BoughtOn.Location())) {
+		n -= p.TodayBought
+	}
+	return n
+}
+
+func commission(cfg backtest.Config, amount float64) float64 {
+	return math.Max(amount*cfg.CommissionRate, cfg.MinCommission)
+}
+
+// blocked reports whether a fill at the quote is impossible because of the price limits.
+func blocked(side entities.TradeSide, detail *spiders.StockWithDetail) string {
+	price := spiders.Value(detail.Price)
+	if side == entities.SideBuy && detail.LimitUp > 0 && price >= detail.LimitUp {
+		return "limit up"
+	}
+	if side == entities.SideSell && detail.LimitDown > 0 && price > 0 && price <= detail.LimitDown {
+		return "limit down"
+	}
+	return ""
+}
+
+// check returns why the order can not be accepted, or an empty string.
+func (s *PaperImpl) check(a *entities.PaperAccount, o *entities.PaperOrder, detail *spiders.StockWithDetail, cfg backtest.Config, now time.Time) string {
+	price := o.Price
+	if o.Type == entities.OrderMarket {
+		price = spiders.Value(detail.Price)
+		if price <= 0 {
+			return "no quote"
+		}
+	} else if (detail.LimitUp > 0 && o.Price > detail.LimitUp) || (detail.LimitDown > 0 && o.Price < detail.LimitDown) {
+		return "price outside limit up/down"
+	}
+	if o.Side == entities.SideBuy {
+		if o.Quantity%cfg.LotSize != 0 {
+			return fmt.Sprintf("quantity must be a multiple of %d", cfg.LotSize)
+		}
+		amount := price * float64(o.Quantity)
+		if amount+commission(cfg, amount) > a.Cash {
+			return "insufficient cash"
+		}
+		return ""
+	}
+	p := a.Positions[o.Code]
+	if p == nil || p.Quantity-p.Frozen <= 0 {
+		return "no position"
+	}
+	available := sellable(p, now, cfg)
+	if o.Quantity > available {
+		if o.Quantity <= p.Quantity-p.Frozen {
+			return "T+1"
+		}
+		return "insufficient position"
+	}
+	if o.Quantity%cfg.LotSize != 0 && o.Quantity != p.Quantity-p.Frozen {
+		return "odd lots must be sold at once"
+	}
+	return ""
+}
+
+// reserve freezes the cash of a buy or the shares of a sell until it fills, is cancelled
+// or expires.
+func reserve(a *entities.PaperAccount, o *entities.PaperOrder, price float64, cfg backtest.Config) {
+	if o.Side == entities.SideBuy {
+		amount := price * float64(o.Quantity)
+		o.Reserved = amount + commission(cfg, amount)
+		a.Cash -= o.Reserved
+		a.FrozenCash += o.Reserved
+		return
+	}
+	a.Positions[o.Code].Frozen += o.Quantity
+}
+
+func release(a *entities.PaperAccount, o *entities.PaperOrder) {
+	if o.Side == entities.SideBuy {
+		a.Cash += o.Reserved
+		a.FrozenCash -= o.Reserved
+		o.Reserved = 0
+		return
+	}
+	if p := a.Positions[o.Code]; p != nil {
+		p.Frozen -= o.Quantity
+	}
+}
+
+// fill executes a reserved order at price.
+func fill(a *entities.PaperAccount, o *entities.PaperOrder, price float64, cfg backtest.Config, now time.Time) *entities.PaperFill {
+	release(a, o)
+	amount := price * float64(o.Quantity)
+	f := &entities.PaperFill{
+		AccountID:  a.ID,
+		Code:       o.Code,
+		Side:       o.Side,
+		Price:      price,
+		Quantity:   o.Quantity,
+		Commission: commission(cfg, amount),
+		Time:       now,
+	}
+	if o.Side == entities.SideBuy {
+		if a.Positions == nil {
+			a.Positions = make(map[string]*entities.PaperPosition)
+		}
+		p := a.Positions[o.Code]
+		if p == nil {
+			p = &entities.PaperPosition{Code: o.Code}
+			a.Positions[o.Code] = p
+		}
+		a.Cash -= amount + f.Commission
+		p.Quantity += o.Quantity
+		p.Cost += amount + f.Commission
+		day := now.In(spiders.MarketOf(o.Code).Location)
+		if !sameDay(p.BoughtOn, day) {
+			p.TodayBought = 0
+		}
+		p.TodayBought += o.Quantity
+		p.BoughtOn = day
+	} else {
+		p := a.Positions[o.Code]
+		f.Tax = amount * cfg.StampTaxRate
+		a.Cash += amount - f.Commission - f.Tax
+		p.Cost -= p.Cost * float64(o.Quantity) / float64(p.Quantity)
+		p.Quantity -= o.Quantity
+		if p.Quantity == 0 {
+			delete(a.Positions, o.Code)
+		}
+	}
+	o.Status = entities.OrderFilled
+	o.FilledPrice = price
+	o.UpdatedAt = now
+	return f
+}
+
+// match fills a reserved order when the market is open and the quote crosses its price.
+func match(a *entities.PaperAccount, o *entities.PaperOrder, detail *spiders.StockWithDetail, cfg backtest.Config, now time.Time) *entities.PaperFill {
+	price := spiders.Value(detail.Price)
+	if price <= 0 || !tradable(o.Code, now) || blocked(o.Side, detail) != "" {
+		return nil
+	}
+	crossed := o.Type == entities.OrderMarket ||
+		(o.Side == entities.SideBuy && price <= o.Price) ||
+		(o.Side == entities.SideSell && price >= o.Price)
+	if !crossed {
+		return nil
+	}
+	return fill(a, o, price, cfg, now)
+}
+
+// PlaceOrder validates and records an order, filling it at once when possible. Orders
+// breaking a trading rule are recorded as rejected with the reason.
+func (s *PaperImpl) PlaceOrder(o *entities.PaperOrder) error {
+	if o.Side != entities.SideBuy && o.Side != entities.SideSell {
+		return fmt.Errorf("%w: side [%s]", ErrInvalidOrder, o.Side)
+	}
+	if o.Type != entities.OrderMarket && o.Type != entities.OrderLimit {
+		return fmt.Errorf("%w: type [%s]", ErrInvalidOrder, o.Type)
+	}
+	if o.Quantity <= 0 || (o.Type == entities.OrderLimit && o.Price <= 0) {
+		return fmt.Errorf("%w: quantity and limit price must be positive", ErrInvalidOrder)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, err := s.PaperStore.PaperAccount(o.AccountID)
+	if err != nil {
+		return err
+	}
+	detail, err := s.stock.Stock(o.Code)
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	cfg := backtest.DefaultConfig(o.Code)
+	o.ID = 0
+	o.Status = entities.OrderPending
+	o.CreatedAt, o.UpdatedAt = now, now
+	reason := s.check(a, o, detail, cfg, now)
+	if reason == "" && o.Type == entities.OrderMarket {
+		if !tradable(o.Code, now) {
+			reason = "market closed"
+		} else {
+			reason = blocked(o.Side, detail)
+		}
+	}
+	if reason != "" {
+		o.Status = entities.OrderRejected
+		o.Reason = reason
+		return s.PaperStore.SavePaperTrade(a, o, nil)
+	}
+	price := o.Price
+	if o.Type == entities.OrderMarket {
+		price = spiders.Value(detail.Price)
+	}
+	reserve(a, o, price, cfg)
+	return s.PaperStore.SavePaperTrade(a, o, match(a, o, detail, cfg, now))
+}
+
+func (s *PaperImpl) CancelOrder(accountID, orderID uint64) (*entities.PaperOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, err := s.PaperStore.PaperAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	o, err := s.PaperStore.PaperOrder(accountID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != entities.OrderPending {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotPending, o.Status)
+	}
+	release(a, o)
+	o.Status = entities.OrderCancelled
+	o.UpdatedAt = s.Now()
+	if err := s.PaperStore.SavePaperTrade(a, o, nil); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// MatchPending tries to fill the pending orders of all accounts against fresh quotes.
+// Orders are day orders, the ones still pending after their trading day closed expire
+// and free their cash or shares.
+func (s *PaperImpl) MatchPending() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts, err := s.PaperStore.PaperAccounts()
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	details := make(map[string]*spiders.StockWithDetail)
+	for _, a := range accounts {
+		orders, err := s.PaperStore.PaperOrders(a.ID)
+		if err != nil {
+			return err
+		}
+		// oldest first, so that earlier orders use the cash first
+		for i := len(orders) - 1; i >= 0; i-- {
+			o := orders[i]
+			if o.Status != entities.OrderPending {
+				continue
+			}
+			if !now.Before(expiry(o.Code, o.CreatedAt)) {
+				release(a, o)
+				o.Status = entities.OrderExpired
+				o.UpdatedAt = now
+				if err := s.PaperStore.SavePaperTrade(a, o, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			if !tradable(o.Code, now) {
+				continue
+			}
+			detail, ok := details[o.Code]
+			if !ok {
+				if detail, err = s.stock.Stock(o.Code); err != nil {
+					logrus.WithFields(logrus.Fields{
+						"code": o.Code,
+					}).Error(err)
+				}
+				details[o.Code] = detail
+			}
+			if detail == nil {
+				continue
+			}
+			if f := match(a, o, detail, backtest.DefaultConfig(o.Code), now); f != nil {
+				if err := s.PaperStore.SavePaperTrade(a, o, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Run matches pending orders every interval until stop is closed.
+func (s *PaperImpl) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.MatchPending(); err != nil {
+				logrus.Error(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"errors"
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaperImpl_OrderLifecycle(t *testing.T) {
+	market := spiders.MarketOf("1.600350")
+	now := time.Date(2020, 11, 2, 10, 0, 0, 0, market.Location) // Monday
+	provider := &fakeProvider{detail: &spiders.StockWithDetail{
+		Stock:     spiders.Stock{InternalCode: "1.600350"},
+		Price:     number(10),
+		LimitUp:   11,
+		LimitDown: 9,
+	}}
+	service := services.NewPaperService(openStore(t), services.NewService(provider, nil))
+	service.Now = func() time.Time { return now }
+
+	a, err := service.CreatePaperAccount("practice", 10000)
+	if !assert.NoError(t, err) {
+		return
+	}
+	place := func(side entities.TradeSide, typ entities.OrderType, price float64, quantity int64) *entities.PaperOrder {
+		o := &entities.PaperOrder{AccountID: a.ID, Code: "1.600350", Side: side, Type: typ, Price: price, Quantity: quantity}
+		assert.NoError(t, service.PlaceOrder(o))
+		return o
+	}
+
+	assert.True(t, errors.Is(service.PlaceOrder(&entities.PaperOrder{AccountID: a.ID, Code: "1.600350", Side: "hold", Type: entities.OrderMarket, Quantity: 100}), services.ErrInvalidOrder))
+	assert.Equal(t, "quantity must be a multiple of 100", place(entities.SideBuy, entities.OrderMarket, 0, 150).Reason)
+	assert.Equal(t, "price outside limit up/down", place(entities.SideBuy, entities.OrderLimit, 12, 100).Reason)
+	assert.Equal(t, "insufficient cash", place(entities.SideBuy, entities.OrderMarket, 0, 1000).Reason)
+
+	bought := place(entities.SideBuy, entities.OrderMarket, 0, 500)
+	assert.Equal(t, entities.OrderFilled, bought.Status)
+	// T+1: the shares bought today can not be sold
+	assert.Equal(t, "T+1", place(entities.SideSell, entities.OrderMarket, 0, 500).Reason)
+
+	// no market orders during the lunch break
+	now = time.Date(2020, 11, 2, 12, 0, 0, 0, market.Location)
+	assert.Equal(t, "market closed", place(entities.SideBuy, entities.OrderMarket, 0, 100).Reason)
+
+	// a limit order left at the close expires and frees its cash
+	expiring := place(entities.SideBuy, entities.OrderLimit, 9.5, 200)
+	assert.Equal(t, entities.OrderPending, expiring.Status)
+	now = time.Date(2020, 11, 2, 15, 1, 0, 0, market.Location)
+	assert.NoError(t, service.MatchPending())
+	orders, _ := service.PaperOrders(a.ID)
+	assert.Equal(t, entities.OrderExpired, orders[0].Status)
+	account, _ := service.PaperAccount(a.ID)
+	assert.InDelta(t, 10000-5000-5, account.Cash, 1e-9)
+	assert.InDelta(t, 0, account.FrozenCash, 1e-9)
+
+	// placed after the close, it is valid for the next day
+	pending := place(entities.SideBuy, entities.OrderLimit, 9.5, 200)
+	assert.Equal(t, entities.OrderPending, pending.Status)
+	account, _ = service.PaperAccount(a.ID)
+	assert.InDelta(t, 10000-5000-5-1900-5, account.Cash, 1e-9)
+	assert.InDelta(t, 1905, account.FrozenCash, 1e-9)
+
+	// the lunch break of the next day does not match, the afternoon does
+	provider.detail.Price = number(9.4)
+	now = time.Date(2020, 11, 3, 12, 0, 0, 0, market.Location)
+	assert.NoError(t, service.MatchPending())
+	orders, _ = service.PaperOrders(a.ID)
+	assert.Equal(t, entities.OrderPending, orders[0].Status)
+	now = time.Date(2020, 11, 3, 13, 30, 0, 0, market.Location)
+	assert.NoError(t, service.MatchPending())
+	orders, _ = service.PaperOrders(a.ID)
+	assert.Equal(t, entities.OrderFilled, orders[0].Status)
+	assert.Equal(t, 9.4, orders[0].FilledPrice)
+
+	sell := place(entities.SideSell, entities.OrderLimit, 10.5, 500)
+	assert.Equal(t, entities.OrderPending, sell.Status)
+	assert.Equal(t, "insufficient position", place(entities.SideSell, entities.OrderMarket, 0, 300).Reason)
+	cancelled, err := service.CancelOrder(a.ID, sell.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, entities.OrderCancelled, cancelled.Status)
+	}
+	_, err = service.CancelOrder(a.ID, sell.ID)
+	assert.True(t, errors.Is(err, services.ErrOrderNotPending))
+
+	// the 200 shares filled today are locked until tomorrow
+	assert.Equal(t, "T+1", place(entities.SideSell, entities.OrderMarket, 0, 700).Reason)
+	sold := place(entities.SideSell, entities.OrderMarket, 0, 500)
+	assert.Equal(t, entities.OrderFilled, sold.Status)
+	account, _ = service.PaperAccount(a.ID)
+	if assert.Contains(t, account.Positions, "1.600350") {
+		assert.Equal(t, int64(200), account.Positions["1.600350"].Quantity)
+	}
+	assert.InDelta(t, 0, account.FrozenCash, 1e-9)
+	fills, _ := service.PaperFills(a.ID)
+	if assert.Len(t, fills, 3) {
+		assert.InDelta(t, 9.4*500*0.0005, fills[0].Tax, 1e-9)
+	}
+
+	// closed market rejects market orders
+	now = time.Date(2020, 11, 7, 10, 0, 0, 0, market.Location) // Saturday
+	assert.Equal(t, "market closed", place(entities.SideBuy, entities.OrderMarket, 0, 100).Reason)
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidTransaction   = errors.New("invalid transaction")
+	ErrInsufficientPosition = errors.New("sell exceeds the position held")
+)
+
+// PortfolioStore persists portfolios and their transactions, see store.Store.
+type PortfolioStore interface {
+	CreatePortfolio(p *entities.Portfolio) error
+	Portfolios() ([]*entities.Portfolio, error)
+	Portfolio(id uint64) (*entities.Portfolio, error)
+	DeletePortfolio(id uint64) error
+	CreateTransaction(t *entities.Transaction) error
+	Transactions(portfolioID uint64) ([]*entities.Transaction, error)
+	DeleteTransaction(portfolioID, id uint64) error
+}
+
+type PortfolioImpl struct {
+	PortfolioStore
+	stock   *StockImpl
+	sectors sync.Map // code to StockWithDetail.Type, which rarely changes
+	Now     func() time.Time
+}
+
+func NewPortfolioService(store PortfolioStore, stock *StockImpl) *PortfolioImpl {
+	return &PortfolioImpl{
+		PortfolioStore: store,
+		stock:          stock,
+		Now:            time.Now,
+	}
+}
+
+func (s *PortfolioImpl) CreatePortfolio(name string) (*entities.Portfolio, error) {
+	p := &entities.Portfolio{Name: name}
+	if err := s.PortfolioStore.CreatePortfolio(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// CreateTransaction records a trade, rejecting sells of more shares than held at its date.
+func (s *PortfolioImpl) CreateTransaction(t *entities.Transaction) error {
+	if t.Side != entities.SideBuy && t.Side != entities.SideSell {
+		return fmt.Errorf("%w: side [%s]", ErrInvalidTransaction, t.Side)
+	}
+	if t.Quantity <= 0 || t.Price <= 0 || t.Fee < 0 {
+		return fmt.Errorf("%w: quantity, price and fee must be positive", ErrInvalidTransaction)
+	}
+	transactions, err := s.PortfolioStore.Transactions(t.PortfolioID)
+	if err != nil {
+		return err
+	}
+	if _, err := holdings(append(transactions, t)); err != nil {
+		return err
+	}
+	return s.PortfolioStore.CreateTransaction(t)
+}
+
+// DeleteTransaction removes a trade unless later sells depend on it.
+func (s *PortfolioImpl) DeleteTransaction(portfolioID, id uint64) error {
+	transactions, err := s.PortfolioStore.Transactions(portfolioID)
+	if err != nil {
+		return err
+	}
+	rest := make([]*entities.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		if t.ID != id {
+			rest = append(rest, t)
+		}
+	}
+	if _, err := holdings(rest); err != nil {
+		return err
+	}
+	return s.PortfolioStore.DeleteTransaction(portfolioID, id)
+}
+
+type holding struct {
+	code     string
+	quantity int64
+	cost     float64
+	realized float64
+}
+
+// holdings replays transactions by date with the average cost method.
+func holdings(transactions []*entities.Transaction) ([]*holding, error) {
+	sorted := make([]*entities.Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	byCode := make(map[string]*holding)
+	list := make([]*holding, 0)
+	for _, t := range sorted {
+		h, ok := byCode[t.Code]
+		if !ok {
+			h = &holding{code: t.Code}
+			byCode[t.Code] = h
+			list = append(list, h)
+		}
+		amount := float64(t.Quantity) * t.Price
+		if t.Side == entities.SideBuy {
+			h.quantity += t.Quantity
+			h.cost += amount + t.Fee
+			continue
+		}
+		if t.Quantity > h.quantity {
+			return nil, fmt.Errorf("%w: %s holds %d on %s", ErrInsufficientPosition, t.Code, h.quantity, t.Date.Format("2006-01-02"))
+		}
+		cost := h.cost * float64(t.Quantity) / float64(h.quantity)
+		h.realized += amount - t.Fee - cost
+		h.cost -= cost
+		h.quantity -= t.Quantity
+	}
+	return list, nil
+}
+
+func (s *PortfolioImpl) sector(code string) string {
+	if sector, ok := s.sectors.Load(code); ok {
+		return sector.(string)
+	}
+	detail, err := s.stock.Stock(code)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code": code,
+		}).Error(err)
+		return ""
+	}
+	s.sectors.Store(code, detail.Type)
+	return detail.Type
+}
+
+// bought returns the shares bought on the trading day of now by code, and what they cost
+// without fees.
+func bought(transactions []*entities.Transaction, now time.Time) (map[string]int64, map[string]float64) {
+	quantities, amounts := make(map[string]int64), make(map[string]float64)
+	for _, t := range transactions {
+		market := spiders.MarketOf(t.Code)
+		if t.Side != entities.SideBuy || !market.TradingDay(t.Date).Equal(market.TradingDay(now)) {
+			continue
+		}
+		quantities[t.Code] += t.Quantity
+		amounts[t.Code] += float64(t.Quantity) * t.Price
+	}
+	return quantities, amounts
+}
+
+// Valuation prices the holdings of a portfolio with live quotes. Suspended securities
+// are valued at their previous close, positions without a quote are left unpriced and out
+// of the totals. The daily change of the shares bought today is from their buy price.
+func (s *PortfolioImpl) Valuation(portfolioID uint64) (*entities.Valuation, error) {
+	transactions, err := s.PortfolioStore.Transactions(portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := holdings(transactions)
+	if err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	valuation := &entities.Valuation{
+		PortfolioID: portfolioID,
+		Positions:   make([]*entities.Position, 0, len(list)),
+		Allocation:  make([]*entities.Allocation, 0),
+		Time:        now,
+	}
+	codes := make([]string, 0, len(list))
+	for _, h := range list {
+		if h.quantity > 0 {
+			codes = append(codes, h.code)
+		}
+	}
+	quotes, err := s.stock.quotes(codes)
+	if err != nil {
+		return nil, err
+	}
+	todayQuantities, todayAmounts := bought(transactions, now)
+	sectors := make(map[string]*entities.Allocation)
+	for _, h := range list {
+		p := &entities.Position{
+			Code:        h.code,
+			Quantity:    h.quantity,
+			Cost:        h.cost,
+			RealizedPnL: h.realized,
+		}
+		valuation.RealizedPnL += p.RealizedPnL
+		valuation.Positions = append(valuation.Positions, p)
+		if quote, ok := quotes[h.code]; ok {
+			p.Name, p.Currency, p.Price, p.PrevClose = quote.Name, quote.Currency, spiders.Value(quote.Price), quote.Close
+			if p.Price == 0 {
+				p.Price = quote.Close
+			}
+		}
+		if h.quantity <= 0 {
+			continue
+		}
+		p.AvgCost = h.cost / float64(h.quantity)
+		p.Sector = s.sector(h.code)
+		if p.Price <= 0 {
+			continue
+		}
+		p.Priced = true
+		p.MarketValue = p.Price * float64(h.quantity)
+		p.UnrealizedPnL = p.MarketValue - h.cost
+		if h.cost > 0 {
+			p.UnrealizedPct = p.UnrealizedPnL / h.cost
+		}
+		today := todayQuantities[h.code]
+		if today > h.quantity {
+			today = h.quantity
+		}
+		if today > 0 {
+			average := todayAmounts[h.code] / float64(todayQuantities[h.code])
+			p.DailyChange = (p.Price - average) * float64(today)
+		}
+		if p.PrevClose > 0 {
+			p.DailyChange += (p.Price - p.PrevClose) * float64(h.quantity-today)
+		}
+		allocation, ok := sectors[p.Sector]
+		if !ok {
+			allocation = &entities.Allocation{Sector: p.Sector}
+			sectors[p.Sector] = allocation
+			valuation.Allocation = append(valuation.Allocation, allocation)
+		}
+		allocation.MarketValue += p.MarketValue
+		valuation.MarketValue += p.MarketValue
+		valuation.Cost += p.Cost
+		valuation.UnrealizedPnL += p.UnrealizedPnL
+		valuation.DailyChange += p.DailyChange
+	}
+	if valuation.MarketValue > 0 {
+		for _, p := range valuation.Positions {
+			p.Weight = p.MarketValue / valuation.MarketValue
+		}
+		for _, a := range valuation.Allocation {
+			a.Weight = a.MarketValue / valuation.MarketValue
+		}
+	}
+	sort.SliceStable(valuation.Positions, func(i, j int) bool {
+		return valuation.Positions[i].MarketValue > valuation.Positions[j].MarketValue
+	})
+	sort.SliceStable(valuation.Allocation, func(i, j int) bool {
+		return valuation.Allocation[i].MarketValue > valuation.Allocation[j].MarketValue
+	})
+	return valuation, nil
+}
@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"errors"
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortfolioImpl_Valuation(t *testing.T) {
+	provider := &fakeProvider{
+		quotes: map[string]*spiders.MultiStock{
+			"1.600000": {Stock: spiders.Stock{InternalCode: "1.600000", Name: "浦发银行"}, Price: number(11), Close: 10},
+			"0.000001": {Stock: spiders.Stock{InternalCode: "0.000001", Name: "平安银行"}, Close: 20},
+			"0.300059": {Stock: spiders.Stock{InternalCode: "0.300059", Name: "东方财富"}, Price: number(30), Close: 30},
+		},
+		details: map[string]*spiders.StockWithDetail{
+			"1.600000": {Stock: spiders.Stock{InternalCode: "1.600000", Type: "银行"}},
+			"0.000001": {Stock: spiders.Stock{InternalCode: "0.000001", Type: "银行"}},
+			"0.300059": {Stock: spiders.Stock{InternalCode: "0.300059", Type: "证券"}},
+		},
+	}
+	service := services.NewPortfolioService(openStore(t), services.NewService(provider, nil))
+	p, err := service.CreatePortfolio("main")
+	if !assert.NoError(t, err) {
+		return
+	}
+	day := func(d int) time.Time {
+		return time.Date(2020, 11, d, 0, 0, 0, 0, time.Local)
+	}
+	trades := []*entities.Transaction{
+		{Code: "1.600000", Side: entities.SideBuy, Quantity: 1000, Price: 9, Fee: 10, Date: day(2)},
+		{Code: "1.600000", Side: entities.SideSell, Quantity: 500, Price: 10, Fee: 5, Date: day(4)},
+		{Code: "0.000001", Side: entities.SideBuy, Quantity: 100, Price: 18, Date: day(3)},
+		{Code: "0.300059", Side: entities.SideBuy, Quantity: 100, Price: 25, Date: day(3)},
+		{Code: "0.300059", Side: entities.SideSell, Quantity: 100, Price: 28, Date: day(5)},
+		{Code: "0.000002", Side: entities.SideBuy, Quantity: 100, Price: 5, Date: day(3)}, // no quote
+	}
+	for _, trade := range trades {
+		trade.PortfolioID = p.ID
+		assert.NoError(t, service.CreateTransaction(trade))
+	}
+	err = service.CreateTransaction(&entities.Transaction{PortfolioID: p.ID, Code: "0.000001", Side: entities.SideSell, Quantity: 200, Price: 20, Date: day(6)})
+	assert.True(t, errors.Is(err, services.ErrInsufficientPosition))
+	// the sell on day 4 needs the buy of day 2
+	assert.True(t, errors.Is(service.DeleteTransaction(p.ID, 1), services.ErrInsufficientPosition))
+
+	// bought today at 19, the daily change is from there
+	service.Now = func() time.Time { return day(6).Add(10 * time.Hour) }
+	assert.NoError(t, service.CreateTransaction(&entities.Transaction{PortfolioID: p.ID, Code: "0.000001", Side: entities.SideBuy, Quantity: 100, Price: 19, Date: day(6)}))
+
+	v, err := service.Valuation(p.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 500*11+200*20, v.MarketValue, 1e-9)
+	assert.InDelta(t, 4505+3700, v.Cost, 1e-9)
+	assert.InDelta(t, 500*10-5-4505+100*28-2500, v.RealizedPnL, 1e-9)
+	assert.InDelta(t, 500*11-4505+200*20-3700, v.UnrealizedPnL, 1e-9)
+	assert.InDelta(t, 500*1+100*1, v.DailyChange, 1e-9)
+	if assert.Len(t, v.Positions, 4) {
+		assert.Equal(t, "1.600000", v.Positions[0].Code)
+		assert.InDelta(t, 9.01, v.Positions[0].AvgCost, 1e-9)
+		assert.Equal(t, int64(0), v.Positions[2].Quantity)
+		assert.Equal(t, "0.000002", v.Positions[3].Code)
+		assert.False(t, v.Positions[3].Priced)
+		assert.Equal(t, float64(0), v.Positions[3].UnrealizedPnL)
+	}
+	if assert.Len(t, v.Allocation, 1) {
+		assert.Equal(t, "银行", v.Allocation[0].Sector)
+		assert.InDelta(t, 1, v.Allocation[0].Weight, 1e-9)
+	}
+}
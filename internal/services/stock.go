@@ -1,21 +1,53 @@
 package services
 
 import (
+	"errors"
 	"stock/internal/entities"
+	"stock/pkg/pattern"
+	"stock/pkg/resample"
 	"stock/pkg/spiders"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
+// ErrUnsupportedProvider is returned by features the stock provider does not implement.
+var ErrUnsupportedProvider = errors.New("not supported by the stock provider")
+
+// KLineStore keeps fetched K-lines locally, see store.Store.
+type KLineStore interface {
+	SaveKLines(code string, t spiders.Type, start, end time.Time, lines []*spiders.KLine) error
+	LoadKLines(code string, t spiders.Type, start, end time.Time) ([]*spiders.KLine, bool, error)
+}
+
+// storedTypes are the periods written to the KLineStore, lowest first. Requests for
+// larger periods are resampled from them when the stored range covers the request.
+var storedTypes = []spiders.Type{spiders.FiveMinutes, spiders.OneDay}
+
 type StockImpl struct {
 	spiders.IStock
+	kLineStore KLineStore
+	symbols    *SymbolMaster
+	Now        func() time.Time
 }
 
-func NewService(s spiders.IStock) *StockImpl {
-	return &StockImpl{s}
+// NewService creates the stock service, kLineStore may be nil to always use the provider.
+func NewService(s spiders.IStock, kLineStore KLineStore) *StockImpl {
+	return &StockImpl{
+		IStock:     s,
+		kLineStore: kLineStore,
+		Now:        time.Now,
+	}
 }
 
-func (s *StockImpl) KLine(stockCode string, t spiders.Type, start, end time.Time) (*entities.KLine, error) {
-	data, err := s.IStock.KLine(stockCode, t, start, end)
+// KLine returns the candles as chart series, with the candlestick patterns found in them
+// when withPatterns is set.
+func (s *StockImpl) KLine(stockCode string, t spiders.Type, start, end time.Time, withPatterns bool) (*entities.KLine, error) {
+	period, err := resample.ParsePeriod(string(t))
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.KLines(stockCode, t, start, end)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +56,7 @@ func (s *StockImpl) KLine(stockCode string, t spiders.Type, start, end time.Time
 		KLine:  make([][]float64, len(data)),
 	}
 	for i, item := range data {
-		if t == spiders.OneHour || t == spiders.ThirtyMinutes || t == spiders.FifteenMinutes || t == spiders.FiveMinutes {
+		if period.Intraday() {
 			kline.Labels[i] = item.Time.Format("15:04")
 		} else {
 			kline.Labels[i] = item.Time.Format("2006-01-02")
@@ -37,15 +69,152 @@ func (s *StockImpl) KLine(stockCode string, t spiders.Type, start, end time.Time
 			item.Low,
 		}
 	}
+	if withPatterns {
+		kline.Patterns = pattern.Detect(data)
+	}
 	return kline, nil
 }
 
+// KLines returns the candles of any period parsed by resample.ParsePeriod, including
+// custom ones such as 2h or 3d. A stored lower period series is used when it covers the
+// range, otherwise the provider is asked for the period itself or the largest provider
+// period that resamples into it.
+func (s *StockImpl) KLines(stockCode string, t spiders.Type, start, end time.Time) ([]*spiders.KLine, error) {
+	period, err := resample.ParsePeriod(string(t))
+	if err != nil {
+		return nil, err
+	}
+	market := spiders.MarketOf(stockCode)
+	if s.kLineStore != nil {
+		for _, st := range storedTypes {
+			if !resample.CanResample(resample.MustParsePeriod(string(st)), period) {
+				continue
+			}
+			lines, covered, err := s.kLineStore.LoadKLines(stockCode, st, start, end)
+			if err != nil {
+				return nil, err
+			}
+			if !covered {
+				continue
+			}
+			if st == t {
+				return lines, nil
+			}
+			return resample.Resample(lines, period, market)
+		}
+	}
+	base := t
+	if !t.Valid() {
+		base = ""
+		for _, st := range spiders.SupportedTypes {
+			if resample.CanResample(resample.MustParsePeriod(string(st)), period) {
+				base = st
+			}
+		}
+		if base == "" {
+			return nil, spiders.ErrUnsupportedType
+		}
+	}
+	lines, err := s.IStock.KLine(stockCode, base, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if s.kLineStore != nil && isStoredType(base) {
+		s.saveKLines(stockCode, base, start, end, lines)
+	}
+	if base == t {
+		return lines, nil
+	}
+	return resample.Resample(lines, period, market)
+}
+
+// saveKLines stores the candles of the sessions already closed, so that the candle in
+// progress is fetched again rather than served from the store.
+func (s *StockImpl) saveKLines(stockCode string, t spiders.Type, start, end time.Time, lines []*spiders.KLine) {
+	settled := spiders.MarketOf(stockCode).Settled(s.Now())
+	if end.After(settled) {
+		end = settled
+		for len(lines) > 0 && !lines[len(lines)-1].Time.Before(settled) {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	if !end.After(start) {
+		return
+	}
+	if err := s.kLineStore.SaveKLines(stockCode, t, start, end, lines); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"code": stockCode,
+			"type": t,
+		}).Error(err)
+	}
+}
+
+func isStoredType(t spiders.Type) bool {
+	for _, st := range storedTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *StockImpl) Trend(stockCode string, day int, showBefore bool) ([]*spiders.Trend, error) {
 	return s.IStock.Trend(stockCode, day, showBefore)
 }
 
-func (s *StockImpl) Search(key string) ([]*spiders.Stock, error) {
-	return s.IStock.Search(key)
+// WithSymbols makes Search answer from the symbol master, and from the provider too
+// when nothing in the master matches exactly or by prefix.
+func (s *StockImpl) WithSymbols(m *SymbolMaster) *StockImpl {
+	s.symbols = m
+	return s
+}
+
+func (s *StockImpl) Search(key string, opts spiders.SearchOptions) ([]*spiders.Stock, error) {
+	var local []*spiders.Stock
+	if s.symbols != nil {
+		stocks, found := s.symbols.Search(key, opts)
+		if found {
+			return stocks, nil
+		}
+		local = stocks
+	}
+	stocks, err := s.IStock.Search(key, opts)
+	if err != nil {
+		if len(local) > 0 {
+			logrus.WithFields(logrus.Fields{
+				"key": key,
+			}).Error(err)
+			return local, nil
+		}
+		return nil, err
+	}
+	if s.symbols != nil {
+		if err := s.symbols.Learn(stocks); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"key": key,
+			}).Error(err)
+		}
+	}
+	return mergeSearch(stocks, local, opts), nil
+}
+
+// mergeSearch appends the local matches missing from the remote results, up to a page.
+func mergeSearch(remote, local []*spiders.Stock, opts spiders.SearchOptions) []*spiders.Stock {
+	if len(local) == 0 {
+		return remote
+	}
+	seen := make(map[string]bool, len(remote))
+	merged := make([]*spiders.Stock, 0, len(remote)+len(local))
+	for _, list := range [][]*spiders.Stock{remote, local} {
+		for _, stock := range list {
+			if seen[stock.InternalCode] {
+				continue
+			}
+			seen[stock.InternalCode] = true
+			merged = append(merged, stock)
+		}
+	}
+	return spiders.FilterSearch(merged, spiders.SearchOptions{PageSize: opts.PageSize, Types: opts.Types})
 }
 
 func (s *StockImpl) Stock(code string) (*spiders.StockWithDetail, error) {
@@ -0,0 +1,258 @@
+package services_test
+
+import (
+	"errors"
+	"stock/internal/services"
+	"stock/pkg/backtest"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProvider answers every capability from its fields and records what it was asked.
+type fakeProvider struct {
+	spiders.IStock
+	requested []spiders.Type
+	candles   []string                    // codes of the klines asked
+	lines     []*spiders.KLine            // klines of the codes not in linesOf
+	linesOf   map[string][]*spiders.KLine // klines by code
+	trends    map[string][]*spiders.Trend
+	detail    *spiders.StockWithDetail            // detail of the codes not in details
+	details   map[string]*spiders.StockWithDetail // detail by code
+	quotes    map[string]*spiders.MultiStock
+	quoted    [][]string // codes of each MultiStock batch
+	scanned   []*spiders.MultiStock
+	symbols   map[spiders.Scope][]*spiders.Symbol
+	remote    []*spiders.Stock
+	searched  int
+	actions   []*spiders.CorporateAction
+	members   []*spiders.Constituent
+	bonds     []*spiders.ConvertibleBond
+	margins   []*spiders.Margin
+	billboard []*spiders.BillboardEntry
+	seatDays  []time.Time
+	connect   []spiders.ConnectDirection
+	topDate   time.Time
+	notices   []*spiders.News
+}
+
+func (p *fakeProvider) KLine(stockCode string, t spiders.Type, start, end time.Time) ([]*spiders.KLine, error) {
+	p.requested = append(p.requested, t)
+	p.candles = append(p.candles, stockCode)
+	if lines, ok := p.linesOf[stockCode]; ok {
+		return lines, nil
+	}
+	return p.lines, nil
+}
+
+func (p *fakeProvider) Trend(stockCode string, day int, showBefore bool) ([]*spiders.Trend, error) {
+	return p.trends[stockCode], nil
+}
+
+func (p *fakeProvider) Stock(code string) (*spiders.StockWithDetail, error) {
+	if detail, ok := p.details[code]; ok {
+		return detail, nil
+	}
+	if p.detail == nil {
+		return nil, errors.New("no quote")
+	}
+	return p.detail, nil
+}
+
+// MultiStock answers the codes it has a quote of in reverse order, as the order of the
+// answer is not the one asked.
+func (p *fakeProvider) MultiStock(codes []string) ([]*spiders.MultiStock, error) {
+	p.quoted = append(p.quoted, codes)
+	stocks := make([]*spiders.MultiStock, 0, len(codes))
+	for i := len(codes) - 1; i >= 0; i-- {
+		if quote, ok := p.quotes[codes[i]]; ok {
+			stocks = append(stocks, quote)
+		}
+	}
+	return stocks, nil
+}
+
+func (p *fakeProvider) Scan(scope spiders.Scope) ([]*spiders.MultiStock, error) {
+	return p.scanned, nil
+}
+
+func (p *fakeProvider) Symbols(scope spiders.Scope) ([]*spiders.Symbol, error) {
+	return p.symbols[scope], nil
+}
+
+func (p *fakeProvider) Search(key string, opts spiders.SearchOptions) ([]*spiders.Stock, error) {
+	p.searched++
+	return p.remote, nil
+}
+
+func (p *fakeProvider) CorporateActions(code string) ([]*spiders.CorporateAction, error) {
+	return p.actions, nil
+}
+
+func (p *fakeProvider) IndexConstituents(code string) ([]*spiders.Constituent, error) {
+	return p.members, nil
+}
+
+func (p *fakeProvider) ConvertibleBonds() ([]*spiders.ConvertibleBond, error) {
+	return p.bonds, nil
+}
+
+func (p *fakeProvider) ConvertibleBond(code string) (*spiders.ConvertibleBond, error) {
+	for _, bond := range p.bonds {
+		if bond.Code == code {
+			return bond, nil
+		}
+	}
+	return nil, errors.New("no bond")
+}
+
+func (p *fakeProvider) Margin(code string, start, end time.Time) ([]*spiders.Margin, error) {
+	return p.margins, nil
+}
+
+func (p *fakeProvider) MarketMargin(start, end time.Time) ([]*spiders.Margin, error) {
+	return p.margins, nil
+}
+
+func (p *fakeProvider) Billboard(code string, start, end time.Time) ([]*spiders.BillboardEntry, error) {
+	return p.billboard, nil
+}
+
+func (p *fakeProvider) BillboardSeats(code string, date time.Time) ([]*spiders.BillboardSeat, error) {
+	p.seatDays = append(p.seatDays, date)
+	return []*spiders.BillboardSeat{{Name: "机构专用", Side: spiders.BillboardBuy}}, nil
+}
+
+func (p *fakeProvider) ConnectIntraday(direction spiders.ConnectDirection) (*spiders.ConnectIntraday, error) {
+	p.connect = append(p.connect, direction)
+	return &spiders.ConnectIntraday{Points: []*spiders.ConnectFlowPoint{}}, nil
+}
+
+func (p *fakeProvider) ConnectHistory(direction spiders.ConnectDirection, start, end time.Time) ([]*spiders.ConnectFlow, error) {
+	p.connect = append(p.connect, direction)
+	return []*spiders.ConnectFlow{}, nil
+}
+
+func (p *fakeProvider) ConnectTopTraded(direction spiders.ConnectDirection, date time.Time) ([]*spiders.ConnectTopStock, error) {
+	p.connect = append(p.connect, direction)
+	p.topDate = date
+	return []*spiders.ConnectTopStock{}, nil
+}
+
+func (p *fakeProvider) News(code string, page, size int) ([]*spiders.News, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) Announcements(code string, page, size int) ([]*spiders.News, error) {
+	if spiders.MarketOf(code).Type != spiders.MarketCN {
+		return nil, spiders.ErrUnsupportedMarket
+	}
+	return p.notices, nil
+}
+
+// bareProvider has none of the optional capabilities.
+type bareProvider struct {
+	spiders.IStock
+}
+
+type fakeStore struct {
+	saved map[spiders.Type][]*spiders.KLine
+}
+
+func (s *fakeStore) SaveKLines(code string, t spiders.Type, start, end time.Time, lines []*spiders.KLine) error {
+	s.saved[t] = lines
+	return nil
+}
+
+func (s *fakeStore) LoadKLines(code string, t spiders.Type, start, end time.Time) ([]*spiders.KLine, bool, error) {
+	lines, ok := s.saved[t]
+	return lines, ok, nil
+}
+
+func hourLines(date time.Time) []*spiders.KLine {
+	lines := make([]*spiders.KLine, 0)
+	for _, hm := range [][2]int{{10, 30}, {11, 30}, {14, 0}, {15, 0}} {
+		lines = append(lines, &spiders.KLine{
+			Open: 1, Close: 2, High: 3, Low: 0.5,
+			Time: date.Add(time.Duration(hm[0])*time.Hour + time.Duration(hm[1])*time.Minute),
+			Type: spiders.OneHour,
+		})
+	}
+	return lines
+}
+
+func TestStockImpl_KLinesCustomPeriod(t *testing.T) {
+	market := spiders.MarketOf("1.600350")
+	date := time.Date(2020, 11, 2, 0, 0, 0, 0, market.Location)
+	provider := &fakeProvider{lines: hourLines(date)}
+	service := services.NewService(provider, nil)
+
+	lines, err := service.KLines("1.600350", spiders.Type("2h"), date, date.AddDate(0, 0, 1))
+	if assert.NoError(t, err) && assert.Len(t, lines, 2) {
+		assert.Equal(t, []spiders.Type{spiders.OneHour}, provider.requested)
+		assert.Equal(t, "11:30", lines[0].Time.Format("15:04"))
+		assert.Equal(t, spiders.Type("2h"), lines[0].Type)
+	}
+}
+
+func TestStockImpl_KLinesFromStore(t *testing.T) {
+	market := spiders.MarketOf("1.600350")
+	date := time.Date(2020, 11, 2, 0, 0, 0, 0, market.Location)
+	fiveMinutes := make([]*spiders.KLine, 0)
+	for m := 9*60 + 35; m <= 15*60; m += 5 {
+		if m > 11*60+30 && m <= 13*60 {
+			continue
+		}
+		fiveMinutes = append(fiveMinutes, &spiders.KLine{
+			Open: 1, Close: 2, High: 3, Low: 0.5,
+			Time: date.Add(time.Duration(m) * time.Minute),
+			Type: spiders.FiveMinutes,
+		})
+	}
+	provider := &fakeProvider{}
+	store := &fakeStore{saved: map[spiders.Type][]*spiders.KLine{spiders.FiveMinutes: fiveMinutes}}
+	service := services.NewService(provider, store)
+
+	lines, err := service.KLines("1.600350", spiders.OneDay, date, date.AddDate(0, 0, 1))
+	if assert.NoError(t, err) && assert.Len(t, lines, 1) {
+		assert.Empty(t, provider.requested)
+		assert.Equal(t, date, lines[0].Time)
+	}
+}
+
+func TestStockImpl_KLinesKeepsSessionInProgress(t *testing.T) {
+	market := spiders.MarketOf("1.600350")
+	date := time.Date(2020, 11, 2, 0, 0, 0, 0, market.Location)
+	provider := &fakeProvider{lines: []*spiders.KLine{
+		{Close: 1, Time: date.AddDate(0, 0, -3), Type: spiders.OneDay},
+		{Close: 2, Time: date, Type: spiders.OneDay},
+	}}
+	store := &fakeStore{saved: map[spiders.Type][]*spiders.KLine{}}
+	service := services.NewService(provider, store)
+	service.Now = func() time.Time { return date.Add(10 * time.Hour) }
+
+	lines, err := service.KLines("1.600350", spiders.OneDay, date.AddDate(0, 0, -7), date.AddDate(0, 0, 1))
+	if assert.NoError(t, err) && assert.Len(t, lines, 2) {
+		assert.Len(t, store.saved[spiders.OneDay], 1)
+	}
+}
+
+func TestStockImpl_BacktestSTLimit(t *testing.T) {
+	day := time.Date(2020, 11, 2, 0, 0, 0, 0, spiders.MarketOf("1.600350").Location)
+	provider := &fakeProvider{
+		lines: []*spiders.KLine{
+			{Open: 3, Close: 3, High: 3, Low: 3, Time: day, Type: spiders.OneDay},
+			{Open: 3.15, Close: 3.15, High: 3.15, Low: 3.15, Time: day.AddDate(0, 0, 1), Type: spiders.OneDay},
+		},
+		// an ST stock limited to 5%
+		detail: &spiders.StockWithDetail{LimitUp: 3.15, LimitDown: 2.85, Close: 3},
+	}
+	service := services.NewService(provider, nil)
+	report, err := service.Backtest("1.600350", spiders.OneDay, day, day.AddDate(0, 0, 1), &backtest.BuyAndHold{}, backtest.DefaultConfig("1.600350"))
+	if assert.NoError(t, err) && assert.Len(t, report.Rejections, 1) {
+		assert.Equal(t, 0.05, report.Config.LimitRatio)
+		assert.Equal(t, "limit up", report.Rejections[0].Reason)
+	}
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"sort"
+	"stock/pkg/spiders"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"github.com/sirupsen/logrus"
+)
+
+// SymbolStore persists the symbol master, see store.Store.
+type SymbolStore interface {
+	SaveSymbols(list []*spiders.Symbol) error
+	SaveSymbol(symbol *spiders.Symbol) error
+	Symbols() ([]*spiders.Symbol, error)
+}
+
+// SymbolScopes are the scopes listed into the symbol master, the first scope listing a
+// secid decides its type.
+var SymbolScopes = []spiders.Scope{
+	spiders.ScopeCN, spiders.ScopeIndex, spiders.ScopeFund, spiders.ScopeBond, spiders.ScopeHK, spiders.ScopeUS,
+	spiders.ScopeFutures,
+}
+
+// SymbolMaster is a local list of all listed securities searched without the network.
+// The lists carry no pinyin, the initials are made from the names on refresh and replaced
+// by the ones of the remote search results, which read polyphonic characters right.
+type SymbolMaster struct {
+	SymbolStore
+	lister  spiders.SymbolLister
+	mu      sync.RWMutex
+	symbols []*spiders.Symbol
+	byCode  map[string]*spiders.Symbol
+}
+
+func NewSymbolMaster(store SymbolStore, lister spiders.SymbolLister) *SymbolMaster {
+	return &SymbolMaster{
+		SymbolStore: store,
+		lister:      lister,
+		byCode:      make(map[string]*spiders.Symbol),
+	}
+}
+
+func (m *SymbolMaster) set(symbols []*spiders.Symbol) {
+	byCode := make(map[string]*spiders.Symbol, len(symbols))
+	for _, symbol := range symbols {
+		byCode[symbol.InternalCode] = symbol
+	}
+	m.symbols, m.byCode = symbols, byCode
+}
+
+// Load reads the master saved by the last refresh.
+func (m *SymbolMaster) Load() error {
+	symbols, err := m.SymbolStore.Symbols()
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(symbols)
+	return nil
+}
+
+func (m *SymbolMaster) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.symbols)
+}
+
+// initialsArgs reads the first letter of each Han character and keeps letters and digits,
+// so 沪深300ETF is HS300ETF and the * of *ST is dropped.
+var initialsArgs = pinyin.Args{
+	Style: pinyin.FirstLetter,
+	Fallback: func(r rune, a pinyin.Args) []string {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return []string{string(r)}
+		}
+		return nil
+	},
+}
+
+// initials returns the pinyin initials of a name in upper case, such as DFCF for 东方财富.
+func initials(name string) string {
+	return strings.ToUpper(strings.Join(pinyin.LazyPinyin(name, initialsArgs), ""))
+}
+
+// Refresh lists all scopes again, keeping the learned pinyin and making the initials of
+// the new names. The master is unchanged when a scope fails.
+func (m *SymbolMaster) Refresh() error {
+	symbols := make([]*spiders.Symbol, 0)
+	seen := make(map[string]bool)
+	for _, scope := range SymbolScopes {
+		list, err := m.lister.Symbols(scope)
+		if err != nil {
+			return err
+		}
+		for _, symbol := range list {
+			if seen[symbol.InternalCode] {
+				continue
+			}
+			seen[symbol.InternalCode] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, symbol := range symbols {
+		if old, ok := m.byCode[symbol.InternalCode]; ok && old.Name == symbol.Name {
+			symbol.PinYin = old.PinYin
+			symbol.Type = old.Type
+		}
+		if symbol.PinYin == "" {
+			symbol.PinYin = initials(symbol.Name)
+		}
+	}
+	if err := m.SymbolStore.SaveSymbols(symbols); err != nil {
+		return err
+	}
+	m.set(symbols)
+	return nil
+}
+
+// Learn copies the pinyin and type name of remote search results into the master.
+func (m *SymbolMaster) Learn(stocks []*spiders.Stock) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stock := range stocks {
+		symbol, ok := m.byCode[stock.InternalCode]
+		if !ok || stock.PinYin == "" || (symbol.PinYin == stock.PinYin && symbol.Type == stock.Type) {
+			continue
+		}
+		symbol.PinYin = stock.PinYin
+		symbol.Type = stock.Type
+		if err := m.SymbolStore.SaveSymbol(symbol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subsequence reports whether the runes of key appear in s in order, so 东财 matches 东方财富.
+func subsequence(s, key string) bool {
+	k := []rune(key)
+	i := 0
+	for _, r := range s {
+		if i < len(k) && r == k[i] {
+			i++
+		}
+	}
+	return i == len(k)
+}
+
+// matchScore ranks a symbol for an upper case key, lower is better and -1 is no match:
+// exact code, name or pinyin, then prefixes, substrings and subsequences of them.
+func matchScore(key string, s *spiders.Symbol) int {
+	name, pinyin := strings.ToUpper(s.Name), strings.ToUpper(s.PinYin)
+	switch {
+	case s.Code == key || name == key || (pinyin != "" && pinyin == key):
+		return 0
+	case strings.HasPrefix(s.Code, key), strings.HasPrefix(name, key), strings.HasPrefix(pinyin, key):
+		return 1
+	case strings.Contains(s.Code, key), strings.Contains(name, key), strings.Contains(pinyin, key):
+		return 2
+	case subsequence(name, key), pinyin != "" && subsequence(pinyin, key):
+		return 3
+	default:
+		return -1
+	}
+}
+
+// typeOrder lists the more looked up types first among equal matches.
+var typeOrder = map[spiders.SecurityType]int{
+	spiders.SecurityAShare:  0,
+	spiders.SecurityIndex:   1,
+	spiders.SecurityFund:    2,
+	spiders.SecurityBond:    3,
+	spiders.SecurityHK:      4,
+	spiders.SecurityUS:      5,
+	spiders.SecurityFutures: 6,
+}
+
+// Search matches key in the master and pages the matches of the option types. found is
+// false when none of those types matches exactly or by prefix, for the caller to merge the
+// remote search into the looser matches, such as funds listed under another name.
+func (m *SymbolMaster) Search(key string, opts spiders.SearchOptions) (stocks []*spiders.Stock, found bool) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if key == "" {
+		return nil, false
+	}
+	type match struct {
+		stock spiders.Stock
+		score int
+	}
+	matches := make([]match, 0)
+	// the stocks are copied under the lock, Learn writes their pinyin and type
+	m.mu.RLock()
+	for _, symbol := range m.symbols {
+		if score := matchScore(key, symbol); score >= 0 {
+			matches = append(matches, match{stock: symbol.Stock, score: score})
+		}
+	}
+	m.mu.RUnlock()
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		ta, oka := typeOrder[a.stock.SecurityType]
+		tb, okb := typeOrder[b.stock.SecurityType]
+		if !oka {
+			ta = len(typeOrder)
+		}
+		if !okb {
+			tb = len(typeOrder)
+		}
+		if ta != tb {
+			return ta < tb
+		}
+		return a.stock.InternalCode < b.stock.InternalCode
+	})
+	all := make([]*spiders.Stock, len(matches))
+	prefixed := make([]*spiders.Stock, 0)
+	for i := range matches {
+		match := &matches[i]
+		all[i] = &match.stock
+		if match.score <= 1 {
+			prefixed = append(prefixed, &match.stock)
+		}
+	}
+	found = len(spiders.FilterSearch(prefixed, spiders.SearchOptions{PageSize: 1, Types: opts.Types})) > 0
+	return spiders.FilterSearch(all, opts), found
+}
+
+// Run refreshes the master every interval until stop is closed, and at once when it is
+// empty.
+func (m *SymbolMaster) Run(interval time.Duration, stop <-chan struct{}) {
+	if m.Len() == 0 {
+		if err := m.Refresh(); err != nil {
+			logrus.Error(err)
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := m.Refresh(); err != nil {
+				logrus.Error(err)
+			}
+		}
+	}
+}
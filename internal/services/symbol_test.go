@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// listedProvider lists a few stocks, an index and a fund.
+func listedProvider() *fakeProvider {
+	symbol := func(secid, name string, scope spiders.Scope) *spiders.Symbol {
+		return &spiders.Symbol{Stock: spiders.Stock{
+			Name: name, Code: secid[len(secid)-6:], InternalCode: secid, SecurityType: spiders.ScopeTypes[scope],
+		}}
+	}
+	return &fakeProvider{symbols: map[spiders.Scope][]*spiders.Symbol{
+		spiders.ScopeCN:    {symbol("0.300059", "东方财富", spiders.ScopeCN), symbol("1.600350", "山东高速", spiders.ScopeCN)},
+		spiders.ScopeIndex: {symbol("1.000300", "沪深300", spiders.ScopeIndex)},
+		spiders.ScopeFund:  {symbol("1.510300", "沪深300ETF", spiders.ScopeFund)},
+	}}
+}
+
+func TestSymbolMaster_Search(t *testing.T) {
+	db := openStore(t)
+	provider := listedProvider()
+	master := services.NewSymbolMaster(db, provider)
+	if !assert.NoError(t, master.Refresh()) {
+		return
+	}
+	assert.Equal(t, 4, master.Len())
+	service := services.NewService(provider, nil).WithSymbols(master)
+
+	stocks, err := service.Search("dfcf", spiders.SearchOptions{})
+	if assert.NoError(t, err) && assert.Len(t, stocks, 1) {
+		// the initials are made locally on refresh
+		assert.Equal(t, "0.300059", stocks[0].InternalCode)
+		assert.Equal(t, "DFCF", stocks[0].PinYin)
+	}
+	stocks, _ = service.Search("HS300", spiders.SearchOptions{})
+	if assert.Len(t, stocks, 2) {
+		assert.Equal(t, "1.000300", stocks[0].InternalCode)
+		assert.Equal(t, "1.510300", stocks[1].InternalCode)
+		assert.Equal(t, "HS300ETF", stocks[1].PinYin)
+	}
+	stocks, _ = service.Search("300", spiders.SearchOptions{})
+	if assert.Len(t, stocks, 3) {
+		// the prefix match first, then substrings by type
+		assert.Equal(t, "0.300059", stocks[0].InternalCode)
+		assert.Equal(t, "1.000300", stocks[1].InternalCode)
+		assert.Equal(t, "1.510300", stocks[2].InternalCode)
+	}
+	assert.Equal(t, 0, provider.searched)
+
+	// looser matches are merged after the remote results
+	provider.remote = []*spiders.Stock{
+		{Name: "东方财富创业板ETF联接A", Code: "012345", InternalCode: "150.012345", PinYin: "DFCFCYBETFLJA", SecurityType: spiders.SecurityFund},
+	}
+	stocks, _ = service.Search("东财", spiders.SearchOptions{})
+	assert.Equal(t, 1, provider.searched)
+	if assert.Len(t, stocks, 2) {
+		assert.Equal(t, "150.012345", stocks[0].InternalCode)
+		assert.Equal(t, "0.300059", stocks[1].InternalCode)
+	}
+	stocks, _ = service.Search("300", spiders.SearchOptions{Types: []spiders.SecurityType{spiders.SecurityFund}})
+	assert.Equal(t, 2, provider.searched)
+	if assert.Len(t, stocks, 2) {
+		assert.Equal(t, "150.012345", stocks[0].InternalCode)
+		assert.Equal(t, "1.510300", stocks[1].InternalCode)
+	}
+
+	// the remote pinyin replaces the local one and survives a restart and a refresh
+	provider.remote = []*spiders.Stock{{Name: "山东高速", Code: "600350", InternalCode: "1.600350", PinYin: "SDGS", Type: "沪A"}}
+	stocks, _ = service.Search("山速", spiders.SearchOptions{})
+	assert.Equal(t, 3, provider.searched)
+	assert.Len(t, stocks, 1)
+	reloaded := services.NewSymbolMaster(db, provider)
+	assert.NoError(t, reloaded.Load())
+	assert.NoError(t, reloaded.Refresh())
+	stocks, found := reloaded.Search("SDGS", spiders.SearchOptions{})
+	assert.True(t, found)
+	if assert.Len(t, stocks, 1) {
+		assert.Equal(t, "沪A", stocks[0].Type)
+	}
+	_, found = reloaded.Search("XYZ", spiders.SearchOptions{})
+	assert.False(t, found)
+}
+
+func TestSymbolMaster_SearchWhileLearning(t *testing.T) {
+	master := services.NewSymbolMaster(openStore(t), listedProvider())
+	if !assert.NoError(t, master.Refresh()) {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			pinyin := "DFCF"
+			if i%2 == 0 {
+				pinyin = "DFCFW"
+			}
+			assert.NoError(t, master.Learn([]*spiders.Stock{{InternalCode: "0.300059", PinYin: pinyin}}))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		stocks, _ := master.Search("DFCF", spiders.SearchOptions{})
+		assert.Len(t, stocks, 1)
+	}
+	<-done
+}
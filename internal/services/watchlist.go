@@ -0,0 +1,87 @@
+package services
+
+import (
+	"stock/internal/entities"
+	"stock/pkg/spiders"
+)
+
+// WatchlistStore persists watchlists, see store.Store.
+type WatchlistStore interface {
+	CreateWatchlist(w *entities.Watchlist) error
+	Watchlists() ([]*entities.Watchlist, error)
+	Watchlist(id uint64) (*entities.Watchlist, error)
+	UpdateWatchlist(w *entities.Watchlist) error
+	DeleteWatchlist(id uint64) error
+}
+
+type WatchlistImpl struct {
+	WatchlistStore
+	stock *StockImpl
+}
+
+func NewWatchlistService(store WatchlistStore, stock *StockImpl) *WatchlistImpl {
+	return &WatchlistImpl{
+		WatchlistStore: store,
+		stock:          stock,
+	}
+}
+
+// uniqueCodes drops repeated codes, keeping the first position.
+func uniqueCodes(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	out := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		out = append(out, code)
+	}
+	return out
+}
+
+func (s *WatchlistImpl) CreateWatchlist(name string, codes []string) (*entities.Watchlist, error) {
+	w := &entities.Watchlist{
+		Name:  name,
+		Codes: uniqueCodes(codes),
+	}
+	if err := s.WatchlistStore.CreateWatchlist(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *WatchlistImpl) UpdateWatchlist(id uint64, name string, codes []string) (*entities.Watchlist, error) {
+	w := &entities.Watchlist{
+		ID:    id,
+		Name:  name,
+		Codes: uniqueCodes(codes),
+	}
+	if err := s.WatchlistStore.UpdateWatchlist(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Quotes returns the MultiStock quotes of the watchlist in its saved order. Codes the
+// provider has no quote for are left out.
+func (s *WatchlistImpl) Quotes(id uint64) ([]*spiders.MultiStock, error) {
+	w, err := s.WatchlistStore.Watchlist(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(w.Codes) == 0 {
+		return make([]*spiders.MultiStock, 0), nil
+	}
+	quotes, err := s.stock.quotes(w.Codes)
+	if err != nil {
+		return nil, err
+	}
+	ordered := make([]*spiders.MultiStock, 0, len(w.Codes))
+	for _, code := range w.Codes {
+		if quote, ok := quotes[code]; ok {
+			ordered = append(ordered, quote)
+		}
+	}
+	return ordered, nil
+}
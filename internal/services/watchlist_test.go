@@ -0,0 +1,44 @@
+package services_test
+
+import (
+	"stock/internal/entities"
+	"stock/internal/services"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryWatchlists struct {
+	services.WatchlistStore
+	w *entities.Watchlist
+}
+
+func (m *memoryWatchlists) CreateWatchlist(w *entities.Watchlist) error {
+	w.ID = 1
+	m.w = w
+	return nil
+}
+
+func (m *memoryWatchlists) Watchlist(id uint64) (*entities.Watchlist, error) {
+	return m.w, nil
+}
+
+func TestWatchlistImpl_Quotes(t *testing.T) {
+	provider := &fakeProvider{quotes: map[string]*spiders.MultiStock{}}
+	for _, code := range []string{"1.600350", "0.300059", "1.510300"} { // not 0.000001, delisted
+		provider.quotes[code] = &spiders.MultiStock{Stock: spiders.Stock{InternalCode: code}}
+	}
+	service := services.NewWatchlistService(&memoryWatchlists{}, services.NewService(provider, nil))
+	w, err := service.CreateWatchlist("mine", []string{"1.600350", "0.300059", "1.600350", "1.510300", "0.000001"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"1.600350", "0.300059", "1.510300", "0.000001"}, w.Codes)
+	quotes, err := service.Quotes(w.ID)
+	if assert.NoError(t, err) && assert.Len(t, quotes, 3) {
+		assert.Equal(t, "1.600350", quotes[0].InternalCode)
+		assert.Equal(t, "0.300059", quotes[1].InternalCode)
+		assert.Equal(t, "1.510300", quotes[2].InternalCode)
+	}
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"encoding/json"
+	"stock/internal/entities"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	alertRuleBucket  = []byte("alert_rule")
+	alertEventBucket = []byte("alert_event")
+)
+
+func (s *Store) CreateAlertRule(rule *entities.AlertRule) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(alertRuleBucket)
+		if err != nil {
+			return err
+		}
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		rule.ID = id
+		rule.CreatedAt = time.Now()
+		return putJSON(bucket, idKey(id), rule)
+	})
+}
+
+func (s *Store) AlertRules() ([]*entities.AlertRule, error) {
+	rules := make([]*entities.AlertRule, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(alertRuleBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			rule := new(entities.AlertRule)
+			if err := json.Unmarshal(v, rule); err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (s *Store) AlertRule(id uint64) (*entities.AlertRule, error) {
+	rule := new(entities.AlertRule)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(alertRuleBucket), idKey(id), rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateAlertRule replaces the rule with rule.ID, keeping its creation time.
+func (s *Store) UpdateAlertRule(rule *entities.AlertRule) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(alertRuleBucket)
+		old := new(entities.AlertRule)
+		if err := getJSON(bucket, idKey(rule.ID), old); err != nil {
+			return err
+		}
+		rule.CreatedAt = old.CreatedAt
+		return putJSON(bucket, idKey(rule.ID), rule)
+	})
+}
+
+func (s *Store) DeleteAlertRule(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return deleteKey(tx.Bucket(alertRuleBucket), idKey(id))
+	})
+}
+
+func (s *Store) CreateAlertEvent(event *entities.AlertEvent) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(alertEventBucket)
+		if err != nil {
+			return err
+		}
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		event.ID = id
+		return putJSON(bucket, idKey(id), event)
+	})
+}
+
+// AlertEvents returns up to limit events, newest first.
+func (s *Store) AlertEvents(limit int) ([]*entities.AlertEvent, error) {
+	events := make([]*entities.AlertEvent, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(alertEventBucket)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil && len(events) < limit; k, v = c.Prev() {
+			event := new(entities.AlertEvent)
+			if err := json.Unmarshal(v, event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"stock/pkg/spiders"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	kLineBucket         = []byte("kline")
+	kLineCoverageBucket = []byte("kline_coverage")
+)
+
+// kLineCoverage is the time range a series has been fetched for, so that gaps in the
+// stored candles (holidays, suspensions) are not mistaken for missing data.
+type kLineCoverage struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func kLineSeries(code string, t spiders.Type) []byte {
+	return []byte(code + "/" + string(t))
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.Unix()))
+	return key
+}
+
+// SaveKLines stores the candles fetched for the range start to end, replacing candles of
+// the same time.
+func (s *Store) SaveKLines(code string, t spiders.Type, start, end time.Time, lines []*spiders.KLine) error {
+	series := kLineSeries(code, t)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(kLineBucket)
+		if err != nil {
+			return err
+		}
+		bucket, err := root.CreateBucketIfNotExists(series)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			data, err := json.Marshal(line)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(timeKey(line.Time), data); err != nil {
+				return err
+			}
+		}
+		coverages, err := tx.CreateBucketIfNotExists(kLineCoverageBucket)
+		if err != nil {
+			return err
+		}
+		coverage := kLineCoverage{Start: start, End: end}
+		if data := coverages.Get(series); data != nil {
+			old := kLineCoverage{}
+			if err := json.Unmarshal(data, &old); err != nil {
+				return err
+			}
+			// keep a single contiguous range, extended while fetches overlap
+			if !old.Start.After(end) && !start.After(old.End) {
+				if old.Start.Before(coverage.Start) {
+					coverage.Start = old.Start
+				}
+				if old.End.After(coverage.End) {
+					coverage.End = old.End
+				}
+			}
+		}
+		data, err := json.Marshal(coverage)
+		if err != nil {
+			return err
+		}
+		return coverages.Put(series, data)
+	})
+}
+
+// LoadKLines returns the stored candles between start and end, and whether the whole
+// range has been fetched before.
+func (s *Store) LoadKLines(code string, t spiders.Type, start, end time.Time) ([]*spiders.KLine, bool, error) {
+	series := kLineSeries(code, t)
+	lines := make([]*spiders.KLine, 0)
+	covered := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if coverages := tx.Bucket(kLineCoverageBucket); coverages != nil {
+			if data := coverages.Get(series); data != nil {
+				coverage := kLineCoverage{}
+				if err := json.Unmarshal(data, &coverage); err != nil {
+					return err
+				}
+				covered = !coverage.Start.After(start) && !coverage.End.Before(end)
+			}
+		}
+		root := tx.Bucket(kLineBucket)
+		if root == nil {
+			return nil
+		}
+		bucket := root.Bucket(series)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		last := timeKey(end)
+		for k, v := c.Seek(timeKey(start)); k != nil && bytes.Compare(k, last) <= 0; k, v = c.Next() {
+			line := new(spiders.KLine)
+			if err := json.Unmarshal(v, line); err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return lines, covered, nil
+}
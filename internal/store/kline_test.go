@@ -0,0 +1,58 @@
+package store_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"stock/internal/store"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openStore(t *testing.T) *store.Store {
+	dir, err := ioutil.TempDir("", "stock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	db, err := store.Open(filepath.Join(dir, "stock.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
+func TestStore_KLines(t *testing.T) {
+	db := openStore(t)
+	start := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	lines := []*spiders.KLine{
+		{Open: 1, Close: 2, High: 3, Low: 0.5, Time: start.Add(time.Hour), Type: spiders.FiveMinutes},
+		{Open: 2, Close: 3, High: 4, Low: 1.5, Time: start.Add(2 * time.Hour), Type: spiders.FiveMinutes},
+	}
+	assert.NoError(t, db.SaveKLines("1.600350", spiders.FiveMinutes, start, start.AddDate(0, 0, 1), lines))
+	assert.NoError(t, db.SaveKLines("1.600350", spiders.FiveMinutes, start.AddDate(0, 0, 1), start.AddDate(0, 0, 2), nil))
+
+	got, covered, err := db.LoadKLines("1.600350", spiders.FiveMinutes, start, start.AddDate(0, 0, 2))
+	if assert.NoError(t, err) {
+		assert.True(t, covered)
+		assert.Len(t, got, 2)
+		assert.Equal(t, 3.0, got[1].Close)
+	}
+	got, covered, err = db.LoadKLines("1.600350", spiders.FiveMinutes, start.Add(90*time.Minute), start.AddDate(0, 0, 3))
+	if assert.NoError(t, err) {
+		assert.False(t, covered)
+		assert.Len(t, got, 1)
+	}
+	_, covered, err = db.LoadKLines("0.300059", spiders.FiveMinutes, start, start.AddDate(0, 0, 1))
+	if assert.NoError(t, err) {
+		assert.False(t, covered)
+	}
+}
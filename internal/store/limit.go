@@ -0,0 +1,79 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"stock/internal/entities"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var limitBoardBucket = []byte("limit_board")
+
+// SaveLimitBoard replaces the board of b.Date.
+func (s *Store) SaveLimitBoard(b *entities.LimitBoard) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(limitBoardBucket)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(b.Date), b)
+	})
+}
+
+func (s *Store) LimitBoard(date string) (*entities.LimitBoard, error) {
+	b := new(entities.LimitBoard)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(limitBoardBucket), []byte(date), b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// LatestLimitBoard returns the board of the latest date saved.
+func (s *Store) LatestLimitBoard() (*entities.LimitBoard, error) {
+	b := new(entities.LimitBoard)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(limitBoardBucket)
+		if bucket == nil {
+			return ErrNotFound
+		}
+		k, v := bucket.Cursor().Last()
+		if k == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(v, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// LimitBoards returns the saved boards dated from from to to (2006-01-02) inclusive,
+// oldest first. Days without a board are left out.
+func (s *Store) LimitBoards(from, to string) ([]*entities.LimitBoard, error) {
+	boards := make([]*entities.LimitBoard, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(limitBoardBucket)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		last := []byte(to)
+		for k, v := c.Seek([]byte(from)); k != nil && bytes.Compare(k, last) <= 0; k, v = c.Next() {
+			b := new(entities.LimitBoard)
+			if err := json.Unmarshal(v, b); err != nil {
+				return err
+			}
+			boards = append(boards, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"errors"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var announcementBucket = []byte("announcement_seen")
+
+// SeenAnnouncements returns the announcement IDs saved for code, polled is false when
+// none were ever saved.
+func (s *Store) SeenAnnouncements(code string) (ids []string, polled bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(announcementBucket), []byte(code), &ids)
+	})
+	if errors.Is(err, ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return ids, true, nil
+}
+
+func (s *Store) SaveSeenAnnouncements(code string, ids []string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(announcementBucket)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(code), ids)
+	})
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"encoding/json"
+	"stock/internal/entities"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	paperAccountBucket = []byte("paper_account")
+	paperOrderBucket   = []byte("paper_order")
+	paperFillBucket    = []byte("paper_fill")
+)
+
+func (s *Store) CreatePaperAccount(a *entities.PaperAccount) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(paperAccountBucket)
+		if err != nil {
+			return err
+		}
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		a.ID = id
+		a.CreatedAt = time.Now()
+		return putJSON(bucket, idKey(id), a)
+	})
+}
+
+func (s *Store) PaperAccounts() ([]*entities.PaperAccount, error) {
+	list := make([]*entities.PaperAccount, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(paperAccountBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			a := new(entities.PaperAccount)
+			if err := json.Unmarshal(v, a); err != nil {
+				return err
+			}
+			list = append(list, a)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Store) PaperAccount(id uint64) (*entities.PaperAccount, error) {
+	a := new(entities.PaperAccount)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(paperAccountBucket), idKey(id), a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// nestedBucket returns the bucket of an account within root, creating both.
+func nestedBucket(tx *bolt.Tx, root []byte, accountID uint64) (*bolt.Bucket, error) {
+	bucket, err := tx.CreateBucketIfNotExists(root)
+	if err != nil {
+		return nil, err
+	}
+	return bucket.CreateBucketIfNotExists(idKey(accountID))
+}
+
+// SavePaperTrade writes the account, the order (created when its ID is 0) and the fill
+// if any in one transaction, so that balances never disagree with the order book.
+func (s *Store) SavePaperTrade(a *entities.PaperAccount, o *entities.PaperOrder, fill *entities.PaperFill) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		accounts := tx.Bucket(paperAccountBucket)
+		if accounts == nil || accounts.Get(idKey(a.ID)) == nil {
+			return ErrNotFound
+		}
+		if err := putJSON(accounts, idKey(a.ID), a); err != nil {
+			return err
+		}
+		if o != nil {
+			orders, err := nestedBucket(tx, paperOrderBucket, a.ID)
+			if err != nil {
+				return err
+			}
+			if o.ID == 0 {
+				if o.ID, err = orders.NextSequence(); err != nil {
+					return err
+				}
+			}
+			if err := putJSON(orders, idKey(o.ID), o); err != nil {
+				return err
+			}
+		}
+		if fill != nil {
+			fills, err := nestedBucket(tx, paperFillBucket, a.ID)
+			if err != nil {
+				return err
+			}
+			if fill.ID, err = fills.NextSequence(); err != nil {
+				return err
+			}
+			if o != nil {
+				fill.OrderID = o.ID
+			}
+			return putJSON(fills, idKey(fill.ID), fill)
+		}
+		return nil
+	})
+}
+
+func (s *Store) PaperOrder(accountID, id uint64) (*entities.PaperOrder, error) {
+	o := new(entities.PaperOrder)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(paperOrderBucket)
+		if root == nil {
+			return ErrNotFound
+		}
+		return getJSON(root.Bucket(idKey(accountID)), idKey(id), o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// PaperOrders returns the orders of an account, newest first.
+func (s *Store) PaperOrders(accountID uint64) ([]*entities.PaperOrder, error) {
+	list := make([]*entities.PaperOrder, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return eachNested(tx, paperOrderBucket, accountID, func(v []byte) error {
+			o := new(entities.PaperOrder)
+			if err := json.Unmarshal(v, o); err != nil {
+				return err
+			}
+			list = append(list, o)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// PaperFills returns the fills of an account, newest first.
+func (s *Store) PaperFills(accountID uint64) ([]*entities.PaperFill, error) {
+	list := make([]*entities.PaperFill, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return eachNested(tx, paperFillBucket, accountID, func(v []byte) error {
+			f := new(entities.PaperFill)
+			if err := json.Unmarshal(v, f); err != nil {
+				return err
+			}
+			list = append(list, f)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func eachNested(tx *bolt.Tx, root []byte, accountID uint64, fn func(v []byte) error) error {
+	bucket := tx.Bucket(root)
+	if bucket == nil {
+		return nil
+	}
+	bucket = bucket.Bucket(idKey(accountID))
+	if bucket == nil {
+		return nil
+	}
+	c := bucket.Cursor()
+	for k, v := c.Last(); k != nil; k, v = c.Prev() {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
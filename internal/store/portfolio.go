@@ -0,0 +1,138 @@
+package store
+
+import (
+	"encoding/json"
+	"stock/internal/entities"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	portfolioBucket   = []byte("portfolio")
+	transactionBucket = []byte("portfolio_transaction")
+)
+
+func (s *Store) CreatePortfolio(p *entities.Portfolio) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(portfolioBucket)
+		if err != nil {
+			return err
+		}
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		p.ID = id
+		p.CreatedAt = time.Now()
+		return putJSON(bucket, idKey(id), p)
+	})
+}
+
+func (s *Store) Portfolios() ([]*entities.Portfolio, error) {
+	list := make([]*entities.Portfolio, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(portfolioBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			p := new(entities.Portfolio)
+			if err := json.Unmarshal(v, p); err != nil {
+				return err
+			}
+			list = append(list, p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Store) Portfolio(id uint64) (*entities.Portfolio, error) {
+	p := new(entities.Portfolio)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(portfolioBucket), idKey(id), p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DeletePortfolio removes the portfolio with its transactions.
+func (s *Store) DeletePortfolio(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteKey(tx.Bucket(portfolioBucket), idKey(id)); err != nil {
+			return err
+		}
+		if bucket := tx.Bucket(transactionBucket); bucket != nil && bucket.Bucket(idKey(id)) != nil {
+			return bucket.DeleteBucket(idKey(id))
+		}
+		return nil
+	})
+}
+
+func (s *Store) CreateTransaction(t *entities.Transaction) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(portfolioBucket) == nil || tx.Bucket(portfolioBucket).Get(idKey(t.PortfolioID)) == nil {
+			return ErrNotFound
+		}
+		root, err := tx.CreateBucketIfNotExists(transactionBucket)
+		if err != nil {
+			return err
+		}
+		bucket, err := root.CreateBucketIfNotExists(idKey(t.PortfolioID))
+		if err != nil {
+			return err
+		}
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		t.ID = id
+		return putJSON(bucket, idKey(id), t)
+	})
+}
+
+// Transactions returns the transactions of a portfolio in the order they were recorded.
+func (s *Store) Transactions(portfolioID uint64) ([]*entities.Transaction, error) {
+	list := make([]*entities.Transaction, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(portfolioBucket) == nil || tx.Bucket(portfolioBucket).Get(idKey(portfolioID)) == nil {
+			return ErrNotFound
+		}
+		root := tx.Bucket(transactionBucket)
+		if root == nil {
+			return nil
+		}
+		bucket := root.Bucket(idKey(portfolioID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			t := new(entities.Transaction)
+			if err := json.Unmarshal(v, t); err != nil {
+				return err
+			}
+			list = append(list, t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Store) DeleteTransaction(portfolioID, id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(transactionBucket)
+		if root == nil {
+			return ErrNotFound
+		}
+		return deleteKey(root.Bucket(idKey(portfolioID)), idKey(id))
+	})
+}
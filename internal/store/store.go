@@ -0,0 +1,56 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store is the embedded database of the service, one bolt bucket per kind of record.
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// ErrNotFound is returned when a record does not exist.
+var ErrNotFound = errors.New("record not found")
+
+func putJSON(bucket *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+// getJSON decodes the record of key, bucket may be nil when nothing was written yet.
+func getJSON(bucket *bolt.Bucket, key []byte, v interface{}) error {
+	if bucket == nil {
+		return ErrNotFound
+	}
+	data := bucket.Get(key)
+	if data == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(data, v)
+}
+
+func deleteKey(bucket *bolt.Bucket, key []byte) error {
+	if bucket == nil || bucket.Get(key) == nil {
+		return ErrNotFound
+	}
+	return bucket.Delete(key)
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"encoding/json"
+	"stock/pkg/spiders"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var symbolBucket = []byte("symbol")
+
+// SaveSymbols replaces the symbol master with list.
+func (s *Store) SaveSymbols(list []*spiders.Symbol) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(symbolBucket) != nil {
+			if err := tx.DeleteBucket(symbolBucket); err != nil {
+				return err
+			}
+		}
+		bucket, err := tx.CreateBucket(symbolBucket)
+		if err != nil {
+			return err
+		}
+		for _, symbol := range list {
+			if err := putJSON(bucket, []byte(symbol.InternalCode), symbol); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Symbols returns the symbol master ordered by secid.
+func (s *Store) Symbols() ([]*spiders.Symbol, error) {
+	list := make([]*spiders.Symbol, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(symbolBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			symbol := new(spiders.Symbol)
+			if err := json.Unmarshal(v, symbol); err != nil {
+				return err
+			}
+			list = append(list, symbol)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SaveSymbol replaces one entry of the symbol master.
+func (s *Store) SaveSymbol(symbol *spiders.Symbol) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(symbolBucket)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(symbol.InternalCode), symbol)
+	})
+}
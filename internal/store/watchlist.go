@@ -0,0 +1,89 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"stock/internal/entities"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var watchlistBucket = []byte("watchlist")
+
+func idKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+func (s *Store) CreateWatchlist(w *entities.Watchlist) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(watchlistBucket)
+		if err != nil {
+			return err
+		}
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		w.ID = id
+		w.CreatedAt = time.Now()
+		w.UpdatedAt = w.CreatedAt
+		return putJSON(bucket, idKey(id), w)
+	})
+}
+
+// Watchlists returns all watchlists in creation order.
+func (s *Store) Watchlists() ([]*entities.Watchlist, error) {
+	list := make([]*entities.Watchlist, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(watchlistBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			w := new(entities.Watchlist)
+			if err := json.Unmarshal(v, w); err != nil {
+				return err
+			}
+			list = append(list, w)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Store) Watchlist(id uint64) (*entities.Watchlist, error) {
+	w := new(entities.Watchlist)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(watchlistBucket), idKey(id), w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// UpdateWatchlist replaces the name and codes of the watchlist with w.ID.
+func (s *Store) UpdateWatchlist(w *entities.Watchlist) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(watchlistBucket)
+		old := new(entities.Watchlist)
+		if err := getJSON(bucket, idKey(w.ID), old); err != nil {
+			return err
+		}
+		w.CreatedAt = old.CreatedAt
+		w.UpdatedAt = time.Now()
+		return putJSON(bucket, idKey(w.ID), w)
+	})
+}
+
+func (s *Store) DeleteWatchlist(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return deleteKey(tx.Bucket(watchlistBucket), idKey(id))
+	})
+}
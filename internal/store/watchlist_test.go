@@ -0,0 +1,38 @@
+package store_test
+
+import (
+	"errors"
+	"stock/internal/entities"
+	"stock/internal/store"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_Watchlists(t *testing.T) {
+	db := openStore(t)
+	_, err := db.Watchlist(1)
+	assert.True(t, errors.Is(err, store.ErrNotFound))
+
+	w := &entities.Watchlist{Name: "banks", Codes: []string{"1.600000", "0.000001"}}
+	if assert.NoError(t, db.CreateWatchlist(w)) {
+		assert.Equal(t, uint64(1), w.ID)
+	}
+	assert.NoError(t, db.CreateWatchlist(&entities.Watchlist{Name: "tech"}))
+
+	w.Codes = []string{"0.000001", "1.600000", "1.601398"}
+	assert.NoError(t, db.UpdateWatchlist(w))
+	got, err := db.Watchlist(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, w.Codes, got.Codes)
+		assert.False(t, got.CreatedAt.IsZero())
+	}
+	assert.True(t, errors.Is(db.UpdateWatchlist(&entities.Watchlist{ID: 9}), store.ErrNotFound))
+
+	assert.NoError(t, db.DeleteWatchlist(2))
+	assert.True(t, errors.Is(db.DeleteWatchlist(2), store.ErrNotFound))
+	list, err := db.Watchlists()
+	if assert.NoError(t, err) && assert.Len(t, list, 1) {
+		assert.Equal(t, "banks", list[0].Name)
+	}
+}
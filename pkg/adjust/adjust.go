@@ -0,0 +1,101 @@
+package adjust
+
+import (
+	"sort"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// Event is the price adjustment of one ex-date, the close before it divided by its
+// ex-rights reference price.
+type Event struct {
+	ExDate time.Time `json:"ex_date"`
+	Factor float64   `json:"factor"`
+}
+
+// ReferencePrice is the ex-rights price an action implies for a previous close, the new
+// rights shares bought at the rights price.
+func ReferencePrice(prevClose float64, a *spiders.CorporateAction) float64 {
+	return (prevClose - a.CashDividend + a.RightsShares*a.RightsPrice) /
+		(1 + a.BonusShares + a.TransferShares + a.RightsShares)
+}
+
+// Factor of an action, 1 when it does not change the price.
+func Factor(prevClose float64, a *spiders.CorporateAction) float64 {
+	ref := ReferencePrice(prevClose, a)
+	if prevClose <= 0 || ref <= 0 {
+		return 1
+	}
+	return prevClose / ref
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Events computes the factors of the actions executed within unadjusted daily lines, in
+// ex-date order. Actions without an ex-date or without a close before it are skipped.
+func Events(lines []*spiders.KLine, actions []*spiders.CorporateAction) []*Event {
+	events := make([]*Event, 0)
+	for _, a := range actions {
+		if a.ExDate.IsZero() {
+			continue
+		}
+		exDate := day(a.ExDate)
+		i := sort.Search(len(lines), func(i int) bool {
+			return !day(lines[i].Time).Before(exDate)
+		})
+		if i == 0 {
+			continue
+		}
+		events = append(events, &Event{ExDate: a.ExDate, Factor: Factor(lines[i-1].Close, a)})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ExDate.Before(events[j].ExDate)
+	})
+	return events
+}
+
+func scale(line *spiders.KLine, f float64) *spiders.KLine {
+	return &spiders.KLine{
+		Open:  line.Open * f,
+		Close: line.Close * f,
+		High:  line.High * f,
+		Low:   line.Low * f,
+		Time:  line.Time,
+		Type:  line.Type,
+	}
+}
+
+// Forward returns copies of the lines adjusted to the latest prices (前复权): prices
+// before each ex-date are divided by its factor.
+func Forward(lines []*spiders.KLine, events []*Event) []*spiders.KLine {
+	out := make([]*spiders.KLine, len(lines))
+	for i, line := range lines {
+		f := 1.0
+		for _, e := range events {
+			if day(line.Time).Before(day(e.ExDate)) {
+				f /= e.Factor
+			}
+		}
+		out[i] = scale(line, f)
+	}
+	return out
+}
+
+// Backward returns copies of the lines adjusted to the first prices (后复权): prices on
+// and after each ex-date are multiplied by its factor.
+func Backward(lines []*spiders.KLine, events []*Event) []*spiders.KLine {
+	out := make([]*spiders.KLine, len(lines))
+	for i, line := range lines {
+		f := 1.0
+		for _, e := range events {
+			if !day(line.Time).Before(day(e.ExDate)) {
+				f *= e.Factor
+			}
+		}
+		out[i] = scale(line, f)
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package adjust_test
+
+import (
+	"stock/pkg/adjust"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	lines := []*spiders.KLine{
+		{Open: 10.5, Close: 10.5, High: 10.5, Low: 10.5, Time: start},
+		{Open: 7.7, Close: 8, High: 8, Low: 7.7, Time: start.AddDate(0, 0, 1)},
+		{Open: 8, Close: 8.2, High: 8.2, Low: 8, Time: start.AddDate(0, 0, 2)},
+	}
+	actions := []*spiders.CorporateAction{
+		// 10转4股派0.7元: (10.5 - 0.07) / 1.4 = 7.45
+		{ExDate: start.AddDate(0, 0, 1), CashDividend: 0.07, TransferShares: 0.4},
+		{Plan: "not executed yet"},
+		{ExDate: start.AddDate(0, 0, -10), CashDividend: 0.1}, // before the lines
+	}
+	events := adjust.Events(lines, actions)
+	if !assert.Len(t, events, 1) {
+		return
+	}
+	assert.InDelta(t, 7.45, adjust.ReferencePrice(10.5, actions[0]), 1e-9)
+	assert.InDelta(t, 10.5/7.45, events[0].Factor, 1e-9)
+
+	forward := adjust.Forward(lines, events)
+	assert.InDelta(t, 7.45, forward[0].Close, 1e-9)
+	assert.Equal(t, 8.0, forward[1].Close)
+	backward := adjust.Backward(lines, events)
+	assert.Equal(t, 10.5, backward[0].Close)
+	assert.InDelta(t, 8.2*10.5/7.45, backward[2].Close, 1e-9)
+	assert.Equal(t, 10.5, lines[0].Close, "the input is not modified")
+}
+
+func TestReferencePrice_Rights(t *testing.T) {
+	// 10配3股 at 5.5 with 0.2 of dividend: (10 - 0.2 + 0.3*5.5) / 1.3
+	a := &spiders.CorporateAction{CashDividend: 0.2, RightsShares: 0.3, RightsPrice: 5.5}
+	assert.InDelta(t, (10-0.2+1.65)/1.3, adjust.ReferencePrice(10, a), 1e-9)
+	assert.InDelta(t, 10/((10-0.2+1.65)/1.3), adjust.Factor(10, a), 1e-9)
+}
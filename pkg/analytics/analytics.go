@@ -0,0 +1,200 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"stock/pkg/spiders"
+	"time"
+)
+
+var ErrNotEnoughData = errors.New("not enough common trading days")
+
+// Series is the analysis of one security over the common trading days.
+type Series struct {
+	Code        string    `json:"code"`
+	Closes      []float64 `json:"closes"`
+	Returns     []float64 `json:"returns"`    // daily simple returns, the first day has none
+	Normalized  []float64 `json:"normalized"` // close divided by the first close
+	Relative    []float64 `json:"relative"`   // normalized divided by the benchmark's
+	TotalReturn float64   `json:"total_return"`
+	Volatility  float64   `json:"volatility"` // standard deviation of daily returns
+	Beta        float64   `json:"beta"`
+}
+
+type Result struct {
+	Dates       []time.Time `json:"dates"`
+	Benchmark   *Series     `json:"benchmark"`
+	Series      []*Series   `json:"series"`
+	Correlation [][]float64 `json:"correlation"` // of daily returns, in the order of Series
+}
+
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// Align keeps the days every series has a candle for, returning the days and the closes
+// of each series on them.
+func Align(series [][]*spiders.KLine) ([]time.Time, [][]float64) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+	counts := make(map[string]int)
+	days := make(map[string]time.Time)
+	for _, lines := range series {
+		seen := make(map[string]bool)
+		for _, line := range lines {
+			key := dayKey(line.Time)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			counts[key]++
+			if _, ok := days[key]; !ok {
+				days[key] = line.Time
+			}
+		}
+	}
+	keys := make([]string, 0)
+	for key, n := range counts {
+		if n == len(series) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	dates := make([]time.Time, len(keys))
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		dates[i] = days[key]
+		index[key] = i
+	}
+	closes := make([][]float64, len(series))
+	for i, lines := range series {
+		closes[i] = make([]float64, len(keys))
+		for _, line := range lines {
+			if j, ok := index[dayKey(line.Time)]; ok {
+				closes[i][j] = line.Close
+			}
+		}
+	}
+	return dates, closes
+}
+
+func returns(closes []float64) []float64 {
+	out := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] == 0 {
+			out = append(out, 0)
+			continue
+		}
+		out = append(out, closes[i]/closes[i-1]-1)
+	}
+	return out
+}
+
+func mean(v []float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x
+	}
+	return sum / float64(len(v))
+}
+
+// covariance is the sample covariance of two series of the same length.
+func covariance(a, b []float64) float64 {
+	if len(a) < 2 {
+		return 0
+	}
+	ma, mb := mean(a), mean(b)
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - ma) * (b[i] - mb)
+	}
+	return sum / float64(len(a)-1)
+}
+
+// Correlation is the Pearson correlation, 0 when a series is constant.
+func Correlation(a, b []float64) float64 {
+	va, vb := covariance(a, a), covariance(b, b)
+	if va == 0 || vb == 0 {
+		return 0
+	}
+	return covariance(a, b) / math.Sqrt(va*vb)
+}
+
+// Beta of returns against the benchmark returns.
+func Beta(r, benchmark []float64) float64 {
+	v := covariance(benchmark, benchmark)
+	if v == 0 {
+		return 0
+	}
+	return covariance(r, benchmark) / v
+}
+
+func analyze(code string, closes []float64) *Series {
+	s := &Series{
+		Code:       code,
+		Closes:     closes,
+		Returns:    returns(closes),
+		Normalized: make([]float64, len(closes)),
+	}
+	for i, c := range closes {
+		s.Normalized[i] = c / closes[0]
+	}
+	s.TotalReturn = s.Normalized[len(closes)-1] - 1
+	s.Volatility = math.Sqrt(covariance(s.Returns, s.Returns))
+	return s
+}
+
+// Compare aligns the daily candles of codes and of the benchmark on their common
+// trading days and relates them to each other.
+func Compare(codes []string, lines [][]*spiders.KLine, benchmark string, benchmarkLines []*spiders.KLine) (*Result, error) {
+	if len(codes) != len(lines) {
+		return nil, fmt.Errorf("%d codes for %d series", len(codes), len(lines))
+	}
+	dates, closes := Align(append(lines, benchmarkLines))
+	if len(dates) < 2 {
+		return nil, ErrNotEnoughData
+	}
+	for i, c := range closes {
+		if c[0] == 0 {
+			name := benchmark
+			if i < len(codes) {
+				name = codes[i]
+			}
+			return nil, fmt.Errorf("first close of %s is 0", name)
+		}
+	}
+	result := &Result{
+		Dates:       dates,
+		Benchmark:   analyze(benchmark, closes[len(codes)]),
+		Series:      make([]*Series, len(codes)),
+		Correlation: make([][]float64, len(codes)),
+	}
+	result.Benchmark.Beta = 1
+	result.Benchmark.Relative = make([]float64, len(dates))
+	for j := range dates {
+		result.Benchmark.Relative[j] = 1
+	}
+	for i, code := range codes {
+		s := analyze(code, closes[i])
+		s.Beta = Beta(s.Returns, result.Benchmark.Returns)
+		s.Relative = make([]float64, len(dates))
+		for j := range dates {
+			s.Relative[j] = s.Normalized[j] / result.Benchmark.Normalized[j]
+		}
+		result.Series[i] = s
+	}
+	for i := range codes {
+		result.Correlation[i] = make([]float64, len(codes))
+		for j := range codes {
+			if i == j {
+				result.Correlation[i][j] = 1
+				continue
+			}
+			result.Correlation[i][j] = Correlation(result.Series[i].Returns, result.Series[j].Returns)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package analytics_test
+
+import (
+	"errors"
+	"stock/pkg/analytics"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+
+// daily builds candles on the given day offsets from start.
+func daily(days []int, closes []float64) []*spiders.KLine {
+	lines := make([]*spiders.KLine, len(days))
+	for i, d := range days {
+		lines[i] = &spiders.KLine{Close: closes[i], Time: start.AddDate(0, 0, d), Type: spiders.OneDay}
+	}
+	return lines
+}
+
+func TestAlign(t *testing.T) {
+	dates, closes := analytics.Align([][]*spiders.KLine{
+		daily([]int{0, 1, 2, 3}, []float64{1, 2, 3, 4}),
+		daily([]int{0, 2, 3, 4}, []float64{10, 30, 40, 50}), // suspended on day 1
+	})
+	if assert.Len(t, dates, 3) {
+		assert.Equal(t, start.AddDate(0, 0, 2), dates[1])
+		assert.Equal(t, []float64{1, 3, 4}, closes[0])
+		assert.Equal(t, []float64{10, 30, 40}, closes[1])
+	}
+}
+
+func TestCompare(t *testing.T) {
+	days := []int{0, 1, 2, 3, 4}
+	benchmark := daily(days, []float64{100, 101, 99, 102, 103})
+	// twice the benchmark returns, and the opposite of them
+	double := []float64{10}
+	inverse := []float64{10}
+	for i := 1; i < len(days); i++ {
+		r := benchmark[i].Close/benchmark[i-1].Close - 1
+		double = append(double, double[i-1]*(1+2*r))
+		inverse = append(inverse, inverse[i-1]*(1-r))
+	}
+	result, err := analytics.Compare(
+		[]string{"0.300059", "1.600350"},
+		[][]*spiders.KLine{daily(days, double), daily(days, inverse)},
+		"1.000001", benchmark,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, result.Dates, 5)
+	assert.InDelta(t, 2, result.Series[0].Beta, 1e-9)
+	assert.InDelta(t, -1, result.Series[1].Beta, 1e-9)
+	assert.InDelta(t, -1, result.Correlation[0][1], 1e-9)
+	assert.Equal(t, 1.0, result.Correlation[1][1])
+	assert.InDelta(t, 0.03, result.Benchmark.TotalReturn, 1e-9)
+	assert.InDelta(t, result.Series[0].Normalized[4]/1.03, result.Series[0].Relative[4], 1e-9)
+
+	_, err = analytics.Compare([]string{"0.300059"}, [][]*spiders.KLine{daily([]int{0}, []float64{1})}, "1.000001", benchmark)
+	assert.True(t, errors.Is(err, analytics.ErrNotEnoughData))
+}
@@ -0,0 +1,320 @@
+package backtest
+
+import (
+	"errors"
+	"math"
+	"stock/pkg/spiders"
+	"strings"
+	"time"
+)
+
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+var ErrNoBars = errors.New("no kline to backtest")
+
+// Config holds the account and the trading rules of a run.
+type Config struct {
+	Code           string  `json:"code"` // secid of the bars, whose market sets the trading days
+	Cash           float64 `json:"cash"`
+	LotSize        int64   `json:"lot_size"`        // buy quantities are rounded down to lots
+	CommissionRate float64 `json:"commission_rate"` // charged on both sides
+	MinCommission  float64 `json:"min_commission"`
+	StampTaxRate   float64 `json:"stamp_tax_rate"` // charged on sells only, 0.05% since 2023-08-28
+	LimitRatio     float64 `json:"limit_ratio"`    // daily price limit, 0 for none
+	T1             bool    `json:"t1"`             // shares bought today can not be sold today
+}
+
+// DefaultConfig returns the A-share rules for the board of code (a secid or plain code),
+// or rules without lots and limits for HK and US securities.
+func DefaultConfig(code string) Config {
+	cfg := Config{
+		Code:           code,
+		Cash:           100000,
+		LotSize:        100,
+		CommissionRate: 0.00025,
+		MinCommission:  5,
+		StampTaxRate:   0.0005,
+		LimitRatio:     LimitRatio(code),
+		T1:             true,
+	}
+	if spiders.MarketOf(code).Type != spiders.MarketCN {
+		cfg.LotSize = 1
+		cfg.StampTaxRate = 0
+		cfg.LimitRatio = 0
+		cfg.T1 = false
+	}
+	return cfg
+}
+
+// LimitRatio returns the daily price limit of an A-share by its board: 20% on ChiNext
+// and STAR, 30% on the Beijing exchange and 10% on the main boards. The code alone does
+// not tell ST stocks and securities without limits apart, see PriceLimitRatio.
+func LimitRatio(code string) float64 {
+	if i := strings.Index(code, "."); i >= 0 {
+		code = code[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(code, "300"), strings.HasPrefix(code, "301"), strings.HasPrefix(code, "688"), strings.HasPrefix(code, "689"):
+		return 0.2
+	case strings.HasPrefix(code, "8"), strings.HasPrefix(code, "4"):
+		return 0.3
+	default:
+		return 0.1
+	}
+}
+
+// PriceLimitRatio derives the daily price limit from the limit up price of a quote and
+// its previous close, so that ST stocks get their 5%, 0 for securities without limits
+// such as indexes.
+func PriceLimitRatio(limitUp, prevClose float64) float64 {
+	if limitUp <= 0 || prevClose <= 0 {
+		return 0
+	}
+	return math.Round((limitUp/prevClose-1)*100) / 100
+}
+
+type Order struct {
+	Side     Side  `json:"side"`
+	Quantity int64 `json:"quantity"`
+}
+
+type Trade struct {
+	Time       time.Time `json:"time"`
+	Side       Side      `json:"side"`
+	Price      float64   `json:"price"`
+	Quantity   int64     `json:"quantity"`
+	Commission float64   `json:"commission"`
+	Tax        float64   `json:"tax"`
+	Profit     float64   `json:"profit"` // realized on sells, after fees of both sides
+}
+
+type Rejection struct {
+	Time     time.Time `json:"time"`
+	Side     Side      `json:"side"`
+	Quantity int64     `json:"quantity"`
+	Reason   string    `json:"reason"`
+}
+
+type Point struct {
+	Time     time.Time `json:"time"`
+	Equity   float64   `json:"equity"`
+	Drawdown float64   `json:"drawdown"` // from the running peak, 0.1 is 10%
+}
+
+type Report struct {
+	Config      Config       `json:"config"`
+	FinalEquity float64      `json:"final_equity"`
+	Return      float64      `json:"return"`
+	MaxDrawdown float64      `json:"max_drawdown"`
+	Equity      []*Point     `json:"equity"`
+	Trades      []*Trade     `json:"trades"`
+	Rejections  []*Rejection `json:"rejections"`
+}
+
+// Strategy is called once per bar after the bar closed. Orders placed from the Context
+// are filled at the open of the next bar.
+type Strategy interface {
+	OnBar(ctx *Context)
+}
+
+// Context is the view of the run given to a Strategy.
+type Context struct {
+	bars     []*spiders.KLine
+	index    int
+	cash     float64
+	position int64
+	cost     float64 // total cost of the position including buy fees
+	today    int64   // shares bought on the current trading day
+	orders   []*Order
+}
+
+// Bars returns the bars up to and including the current one.
+func (c *Context) Bars() []*spiders.KLine {
+	return c.bars[:c.index+1]
+}
+
+func (c *Context) Bar() *spiders.KLine {
+	return c.bars[c.index]
+}
+
+func (c *Context) Cash() float64 {
+	return c.cash
+}
+
+func (c *Context) Position() int64 {
+	return c.position
+}
+
+func (c *Context) Buy(quantity int64) {
+	c.orders = append(c.orders, &Order{Side: Buy, Quantity: quantity})
+}
+
+func (c *Context) Sell(quantity int64) {
+	c.orders = append(c.orders, &Order{Side: Sell, Quantity: quantity})
+}
+
+// Run replays bars, which must be in time order, through the strategy.
+func Run(bars []*spiders.KLine, strategy Strategy, cfg Config) (*Report, error) {
+	if len(bars) == 0 {
+		return nil, ErrNoBars
+	}
+	if cfg.LotSize <= 0 {
+		cfg.LotSize = 1
+	}
+	e := &engine{
+		cfg:    cfg,
+		market: spiders.MarketOf(cfg.Code),
+		ctx:    &Context{bars: bars, cash: cfg.Cash},
+		report: &Report{
+			Config:     cfg,
+			Equity:     make([]*Point, 0, len(bars)),
+			Trades:     make([]*Trade, 0),
+			Rejections: make([]*Rejection, 0),
+		},
+	}
+	peak := cfg.Cash
+	for i, bar := range bars {
+		e.ctx.index = i
+		e.newDay(bar)
+		orders := e.ctx.orders
+		e.ctx.orders = nil
+		for _, order := range orders {
+			e.fill(bar, order)
+		}
+		equity := e.ctx.cash + float64(e.ctx.position)*bar.Close
+		if equity > peak {
+			peak = equity
+		}
+		drawdown := 0.0
+		if peak > 0 {
+			drawdown = (peak - equity) / peak
+		}
+		if drawdown > e.report.MaxDrawdown {
+			e.report.MaxDrawdown = drawdown
+		}
+		e.report.Equity = append(e.report.Equity, &Point{Time: bar.Time, Equity: equity, Drawdown: drawdown})
+		strategy.OnBar(e.ctx)
+	}
+	e.report.FinalEquity = e.report.Equity[len(e.report.Equity)-1].Equity
+	if cfg.Cash > 0 {
+		e.report.Return = e.report.FinalEquity/cfg.Cash - 1
+	}
+	return e.report, nil
+}
+
+type engine struct {
+	cfg       Config
+	market    *spiders.Market
+	ctx       *Context
+	report    *Report
+	day       time.Time
+	prevClose float64 // close of the previous trading day
+	lastClose float64
+}
+
+func (e *engine) newDay(bar *spiders.KLine) {
+	// the night session of futures belongs to the next trading day
+	day := e.market.TradingDay(bar.Time)
+	if !day.Equal(e.day) {
+		e.day = day
+		e.prevClose = e.lastClose
+		e.ctx.today = 0
+	}
+	e.lastClose = bar.Close
+}
+
+func (e *engine) reject(bar *spiders.KLine, order *Order, reason string) {
+	e.report.Rejections = append(e.report.Rejections, &Rejection{
+		Time:     bar.Time,
+		Side:     order.Side,
+		Quantity: order.Quantity,
+		Reason:   reason,
+	})
+}
+
+func (e *engine) commission(amount float64) float64 {
+	return math.Max(amount*e.cfg.CommissionRate, e.cfg.MinCommission)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func (e *engine) fill(bar *spiders.KLine, order *Order) {
+	price := bar.Open
+	limited := e.cfg.LimitRatio > 0 && e.prevClose > 0
+	switch order.Side {
+	case Buy:
+		if limited && price >= round2(e.prevClose*(1+e.cfg.LimitRatio)) {
+			e.reject(bar, order, "limit up")
+			return
+		}
+		quantity := order.Quantity / e.cfg.LotSize * e.cfg.LotSize
+		for quantity > 0 && price*float64(quantity)+e.commission(price*float64(quantity)) > e.ctx.cash {
+			quantity -= e.cfg.LotSize
+		}
+		if quantity <= 0 {
+			e.reject(bar, order, "insufficient cash or below lot size")
+			return
+		}
+		amount := price * float64(quantity)
+		commission := e.commission(amount)
+		e.ctx.cash -= amount + commission
+		e.ctx.position += quantity
+		e.ctx.cost += amount + commission
+		e.ctx.today += quantity
+		e.report.Trades = append(e.report.Trades, &Trade{
+			Time:       bar.Time,
+			Side:       Buy,
+			Price:      price,
+			Quantity:   quantity,
+			Commission: commission,
+		})
+	case Sell:
+		if limited && price <= round2(e.prevClose*(1-e.cfg.LimitRatio)) {
+			e.reject(bar, order, "limit down")
+			return
+		}
+		sellable := e.ctx.position
+		if e.cfg.T1 {
+			sellable -= e.ctx.today
+		}
+		quantity := order.Quantity
+		if quantity > sellable {
+			quantity = sellable
+		}
+		// odd lots can only be sold when closing the whole position
+		if quantity < e.ctx.position {
+			quantity = quantity / e.cfg.LotSize * e.cfg.LotSize
+		}
+		if quantity <= 0 {
+			reason := "no position"
+			if e.ctx.position > 0 {
+				reason = "T+1"
+			}
+			e.reject(bar, order, reason)
+			return
+		}
+		amount := price * float64(quantity)
+		commission := e.commission(amount)
+		tax := amount * e.cfg.StampTaxRate
+		cost := e.ctx.cost * float64(quantity) / float64(e.ctx.position)
+		e.ctx.cash += amount - commission - tax
+		e.ctx.cost -= cost
+		e.ctx.position -= quantity
+		e.report.Trades = append(e.report.Trades, &Trade{
+			Time:       bar.Time,
+			Side:       Sell,
+			Price:      price,
+			Quantity:   quantity,
+			Commission: commission,
+			Tax:        tax,
+			Profit:     amount - commission - tax - cost,
+		})
+	}
+}
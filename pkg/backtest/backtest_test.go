@@ -0,0 +1,147 @@
+package backtest_test
+
+import (
+	"errors"
+	"stock/pkg/backtest"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dailyBars(opens, closes []float64) []*spiders.KLine {
+	start := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	bars := make([]*spiders.KLine, len(opens))
+	for i := range opens {
+		high, low := opens[i], closes[i]
+		if low > high {
+			high, low = low, high
+		}
+		bars[i] = &spiders.KLine{Open: opens[i], Close: closes[i], High: high, Low: low, Time: start.AddDate(0, 0, i), Type: spiders.OneDay}
+	}
+	return bars
+}
+
+// scripted places orders on given bar indexes.
+type scripted map[int]backtest.Order
+
+func (s scripted) OnBar(ctx *backtest.Context) {
+	if order, ok := s[len(ctx.Bars())-1]; ok {
+		if order.Side == backtest.Buy {
+			ctx.Buy(order.Quantity)
+		} else {
+			ctx.Sell(order.Quantity)
+		}
+	}
+}
+
+func TestRun_FeesAndLots(t *testing.T) {
+	bars := dailyBars([]float64{10, 10, 11, 12}, []float64{10, 10.5, 11.5, 12})
+	strategy := scripted{
+		0: {Side: backtest.Buy, Quantity: 1050},
+		2: {Side: backtest.Sell, Quantity: 1000},
+	}
+	report, err := backtest.Run(bars, strategy, backtest.DefaultConfig("1.600350"))
+	if assert.NoError(t, err) && assert.Len(t, report.Trades, 2) {
+		buy, sell := report.Trades[0], report.Trades[1]
+		assert.Equal(t, int64(1000), buy.Quantity)
+		assert.Equal(t, 10.0, buy.Price)
+		assert.Equal(t, 5.0, buy.Commission)
+		assert.Equal(t, 12.0, sell.Price)
+		assert.InDelta(t, 6.0, sell.Tax, 1e-9)
+		assert.InDelta(t, 12000-5-6-10005, sell.Profit, 1e-9)
+		assert.InDelta(t, 100000+12000-5-6-10005, report.FinalEquity, 1e-9)
+	}
+}
+
+func TestRun_T1(t *testing.T) {
+	bars := make([]*spiders.KLine, 0)
+	day := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	for _, hour := range []int{10, 11, 14} {
+		bars = append(bars, &spiders.KLine{Open: 10, Close: 10, High: 10, Low: 10, Time: day.Add(time.Duration(hour) * time.Hour), Type: spiders.OneHour})
+	}
+	bars = append(bars, &spiders.KLine{Open: 10, Close: 10, High: 10, Low: 10, Time: day.AddDate(0, 0, 1).Add(10 * time.Hour), Type: spiders.OneHour})
+	strategy := scripted{
+		0: {Side: backtest.Buy, Quantity: 100},
+		1: {Side: backtest.Sell, Quantity: 100},
+		2: {Side: backtest.Sell, Quantity: 100},
+	}
+	report, err := backtest.Run(bars, strategy, backtest.DefaultConfig("1.600350"))
+	if assert.NoError(t, err) {
+		assert.Len(t, report.Trades, 2)
+		if assert.Len(t, report.Rejections, 1) {
+			assert.Equal(t, "T+1", report.Rejections[0].Reason)
+		}
+	}
+}
+
+func TestRun_T1NightSession(t *testing.T) {
+	market := spiders.MarketOf("113.rb2105")
+	monday := time.Date(2020, 11, 9, 0, 0, 0, 0, market.Location)
+	bars := make([]*spiders.KLine, 0)
+	// Monday night and Tuesday morning are the same trading day
+	for _, minute := range []int{21 * 60, 22 * 60, 24*60 + 30, 33 * 60} {
+		bars = append(bars, &spiders.KLine{Open: 10, Close: 10, High: 10, Low: 10, Time: monday.Add(time.Duration(minute) * time.Minute), Type: spiders.OneHour})
+	}
+	cfg := backtest.DefaultConfig("113.rb2105")
+	cfg.T1 = true
+	strategy := scripted{
+		0: {Side: backtest.Buy, Quantity: 1},
+		2: {Side: backtest.Sell, Quantity: 1},
+	}
+	report, err := backtest.Run(bars, strategy, cfg)
+	if assert.NoError(t, err) {
+		assert.Len(t, report.Trades, 1)
+		if assert.Len(t, report.Rejections, 1) {
+			assert.Equal(t, "T+1", report.Rejections[0].Reason)
+		}
+	}
+}
+
+func TestRun_LimitUp(t *testing.T) {
+	bars := dailyBars([]float64{10, 11, 11}, []float64{10, 11, 11})
+	report, err := backtest.Run(bars, scripted{0: {Side: backtest.Buy, Quantity: 100}}, backtest.DefaultConfig("1.600350"))
+	if assert.NoError(t, err) {
+		assert.Empty(t, report.Trades)
+		if assert.Len(t, report.Rejections, 1) {
+			assert.Equal(t, "limit up", report.Rejections[0].Reason)
+		}
+	}
+	// ChiNext allows 20%
+	report, err = backtest.Run(bars, scripted{0: {Side: backtest.Buy, Quantity: 100}}, backtest.DefaultConfig("0.300059"))
+	if assert.NoError(t, err) {
+		assert.Len(t, report.Trades, 1)
+	}
+}
+
+func TestRun_Drawdown(t *testing.T) {
+	bars := dailyBars([]float64{10, 10, 8, 9}, []float64{10, 10, 8, 9})
+	cfg := backtest.DefaultConfig("1.600350")
+	cfg.CommissionRate, cfg.MinCommission = 0, 0
+	cfg.Cash = 1000
+	report, err := backtest.Run(bars, new(backtest.BuyAndHold), cfg)
+	if assert.NoError(t, err) {
+		assert.InDelta(t, 0.2, report.MaxDrawdown, 1e-9)
+		assert.InDelta(t, -0.1, report.Return, 1e-9)
+		assert.Len(t, report.Equity, 4)
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	_, err := backtest.NewStrategy("sma_cross", map[string]int{"fast": 10, "slow": 5})
+	assert.Error(t, err)
+	_, err = backtest.NewStrategy("unknown", nil)
+	assert.True(t, errors.Is(err, backtest.ErrUnknownStrategy))
+	s, err := backtest.NewStrategy("sma_cross", nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &backtest.SMACross{Fast: 5, Slow: 20}, s)
+	}
+}
+
+func TestPriceLimitRatio(t *testing.T) {
+	assert.Equal(t, 0.1, backtest.PriceLimitRatio(11, 10))
+	assert.Equal(t, 0.05, backtest.PriceLimitRatio(3.15, 3), "ST")
+	assert.Equal(t, 0.2, backtest.PriceLimitRatio(21.44, 17.87))
+	assert.Equal(t, float64(0), backtest.PriceLimitRatio(0, 3300), "index")
+}
@@ -0,0 +1,87 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// StrategyNames lists the built-in strategies accepted by NewStrategy.
+var StrategyNames = []string{"buy_and_hold", "sma_cross"}
+
+// NewStrategy creates a built-in strategy by name. sma_cross reads the "fast" and "slow"
+// window lengths from params, defaulting to 5 and 20.
+func NewStrategy(name string, params map[string]int) (Strategy, error) {
+	switch name {
+	case "buy_and_hold":
+		return new(BuyAndHold), nil
+	case "sma_cross":
+		s := &SMACross{Fast: params["fast"], Slow: params["slow"]}
+		if s.Fast == 0 {
+			s.Fast = 5
+		}
+		if s.Slow == 0 {
+			s.Slow = 20
+		}
+		if s.Fast < 1 || s.Fast >= s.Slow {
+			return nil, fmt.Errorf("sma_cross needs 0 < fast < slow, got %d and %d", s.Fast, s.Slow)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("%w [%s]", ErrUnknownStrategy, name)
+	}
+}
+
+// affordable returns the number of shares the cash buys at price, fees aside.
+func affordable(ctx *Context, price float64) int64 {
+	if price <= 0 {
+		return 0
+	}
+	return int64(math.Floor(ctx.Cash() / price))
+}
+
+// BuyAndHold invests all cash on the first bar and never sells.
+type BuyAndHold struct {
+	bought bool
+}
+
+func (s *BuyAndHold) OnBar(ctx *Context) {
+	if s.bought {
+		return
+	}
+	s.bought = true
+	ctx.Buy(affordable(ctx, ctx.Bar().Close))
+}
+
+// SMACross goes all in when the fast simple moving average of closes crosses above the
+// slow one and sells everything when it crosses below.
+type SMACross struct {
+	Fast int
+	Slow int
+}
+
+func sma(ctx *Context, n, offset int) float64 {
+	bars := ctx.Bars()
+	bars = bars[:len(bars)-offset]
+	sum := 0.0
+	for _, bar := range bars[len(bars)-n:] {
+		sum += bar.Close
+	}
+	return sum / float64(n)
+}
+
+func (s *SMACross) OnBar(ctx *Context) {
+	if len(ctx.Bars()) <= s.Slow {
+		return
+	}
+	fast, slow := sma(ctx, s.Fast, 0), sma(ctx, s.Slow, 0)
+	prevFast, prevSlow := sma(ctx, s.Fast, 1), sma(ctx, s.Slow, 1)
+	switch {
+	case prevFast <= prevSlow && fast > slow && ctx.Position() == 0:
+		ctx.Buy(affordable(ctx, ctx.Bar().Close))
+	case prevFast >= prevSlow && fast < slow && ctx.Position() > 0:
+		ctx.Sell(ctx.Position())
+	}
+}
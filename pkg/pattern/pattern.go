@@ -0,0 +1,137 @@
+package pattern
+
+import (
+	"math"
+	"stock/pkg/spiders"
+	"time"
+)
+
+type Name string
+
+const (
+	Doji             Name = "doji"
+	Hammer           Name = "hammer"
+	BullishEngulfing Name = "bullish_engulfing"
+	BearishEngulfing Name = "bearish_engulfing"
+	MorningStar      Name = "morning_star"
+	EveningStar      Name = "evening_star"
+	GapUp            Name = "gap_up"
+	GapDown          Name = "gap_down"
+)
+
+type Direction string
+
+const (
+	Bullish Direction = "bullish"
+	Bearish Direction = "bearish"
+	Neutral Direction = "neutral"
+)
+
+// Occurrence is a pattern completed by the candle at Index.
+type Occurrence struct {
+	Index     int       `json:"index"`
+	Time      time.Time `json:"time"`
+	Pattern   Name      `json:"pattern"`
+	Direction Direction `json:"direction"`
+}
+
+type candle struct {
+	*spiders.KLine
+}
+
+func (c candle) body() float64 {
+	return math.Abs(c.Close - c.Open)
+}
+
+func (c candle) rangeSize() float64 {
+	return c.High - c.Low
+}
+
+func (c candle) upperShadow() float64 {
+	return c.High - math.Max(c.Open, c.Close)
+}
+
+func (c candle) lowerShadow() float64 {
+	return math.Min(c.Open, c.Close) - c.Low
+}
+
+func (c candle) bullish() bool {
+	return c.Close > c.Open
+}
+
+func (c candle) bearish() bool {
+	return c.Close < c.Open
+}
+
+// Detect scans lines, which must be in time order, and returns the patterns found in
+// order of the candle completing them.
+func Detect(lines []*spiders.KLine) []*Occurrence {
+	out := make([]*Occurrence, 0)
+	add := func(i int, name Name, direction Direction) {
+		out = append(out, &Occurrence{Index: i, Time: lines[i].Time, Pattern: name, Direction: direction})
+	}
+	for i := range lines {
+		c := candle{lines[i]}
+		if isDoji(c) {
+			add(i, Doji, Neutral)
+		} else if isHammer(c) {
+			add(i, Hammer, Bullish)
+		}
+		if i < 1 {
+			continue
+		}
+		prev := candle{lines[i-1]}
+		switch {
+		case c.Low > prev.High:
+			add(i, GapUp, Bullish)
+		case c.High < prev.Low:
+			add(i, GapDown, Bearish)
+		}
+		switch {
+		case prev.bearish() && c.bullish() && c.Open <= prev.Close && c.Close >= prev.Open && c.body() > prev.body():
+			add(i, BullishEngulfing, Bullish)
+		case prev.bullish() && c.bearish() && c.Open >= prev.Close && c.Close <= prev.Open && c.body() > prev.body():
+			add(i, BearishEngulfing, Bearish)
+		}
+		if i < 2 {
+			continue
+		}
+		first := candle{lines[i-2]}
+		switch {
+		case isMorningStar(first, prev, c):
+			add(i, MorningStar, Bullish)
+		case isEveningStar(first, prev, c):
+			add(i, EveningStar, Bearish)
+		}
+	}
+	return out
+}
+
+// isDoji: the body is at most a tenth of the range.
+func isDoji(c candle) bool {
+	return c.rangeSize() > 0 && c.body() <= 0.1*c.rangeSize()
+}
+
+// isHammer: a small body at the top with a lower shadow at least twice the body.
+func isHammer(c candle) bool {
+	return c.body() > 0 && c.lowerShadow() >= 2*c.body() && c.upperShadow() <= 0.1*c.rangeSize()
+}
+
+// long reports whether the body is at least half the range.
+func long(c candle) bool {
+	return c.rangeSize() > 0 && c.body() >= 0.5*c.rangeSize()
+}
+
+// isMorningStar: a long bearish candle, a small body below its close, and a bullish
+// candle closing above the middle of the first body.
+func isMorningStar(first, star, last candle) bool {
+	return first.bearish() && long(first) &&
+		star.body() <= 0.3*first.body() && math.Max(star.Open, star.Close) < first.Close &&
+		last.bullish() && last.Close > (first.Open+first.Close)/2
+}
+
+func isEveningStar(first, star, last candle) bool {
+	return first.bullish() && long(first) &&
+		star.body() <= 0.3*first.body() && math.Min(star.Open, star.Close) > first.Close &&
+		last.bearish() && last.Close < (first.Open+first.Close)/2
+}
@@ -0,0 +1,64 @@
+package pattern_test
+
+import (
+	"stock/pkg/pattern"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bars builds daily candles from open, close, high, low quadruples.
+func bars(ohlc ...[4]float64) []*spiders.KLine {
+	start := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	lines := make([]*spiders.KLine, len(ohlc))
+	for i, v := range ohlc {
+		lines[i] = &spiders.KLine{Open: v[0], Close: v[1], High: v[2], Low: v[3], Time: start.AddDate(0, 0, i), Type: spiders.OneDay}
+	}
+	return lines
+}
+
+func names(occurrences []*pattern.Occurrence) map[int][]pattern.Name {
+	out := make(map[int][]pattern.Name)
+	for _, o := range occurrences {
+		out[o.Index] = append(out[o.Index], o.Pattern)
+	}
+	return out
+}
+
+func TestDetect_SingleCandles(t *testing.T) {
+	found := names(pattern.Detect(bars(
+		[4]float64{10, 10.01, 10.5, 9.5}, // doji
+		[4]float64{10, 10.2, 10.22, 9.5}, // hammer
+		[4]float64{10, 11, 11, 10},       // plain bullish
+	)))
+	assert.Equal(t, []pattern.Name{pattern.Doji}, found[0])
+	assert.Equal(t, []pattern.Name{pattern.Hammer}, found[1])
+	assert.Empty(t, found[2])
+}
+
+func TestDetect_Engulfing(t *testing.T) {
+	found := names(pattern.Detect(bars(
+		[4]float64{10.5, 10, 10.6, 9.9},
+		[4]float64{9.9, 10.8, 10.9, 9.8},
+		[4]float64{10.9, 9.7, 11, 9.6},
+	)))
+	assert.Contains(t, found[1], pattern.BullishEngulfing)
+	assert.Contains(t, found[2], pattern.BearishEngulfing)
+}
+
+func TestDetect_Stars(t *testing.T) {
+	found := names(pattern.Detect(bars(
+		[4]float64{11, 10, 11.1, 9.9},
+		[4]float64{9.7, 9.75, 9.8, 9.6}, // gaps below the first candle
+		[4]float64{9.9, 10.8, 10.9, 9.85},
+		[4]float64{12, 13, 13.1, 11.9},
+		[4]float64{13.3, 13.25, 13.4, 13.2},
+		[4]float64{13.1, 12.2, 13.15, 12.1},
+	)))
+	assert.Contains(t, found[1], pattern.GapDown)
+	assert.Contains(t, found[2], pattern.MorningStar)
+	assert.Contains(t, found[3], pattern.GapUp)
+	assert.Contains(t, found[5], pattern.EveningStar)
+}
@@ -0,0 +1,122 @@
+package resample
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"stock/pkg/spiders"
+	"strconv"
+)
+
+type Unit int
+
+const (
+	Minute Unit = iota
+	Day
+	Week
+	Month
+)
+
+// Period is a K-line period such as 5min, 2h, 3d or 1q. Hours are kept as minutes and
+// quarters/years as months so that periods of the same unit compare by N alone.
+type Period struct {
+	N    int
+	Unit Unit
+}
+
+// PeriodPattern is the grammar of the periods ParsePeriod accepts, a positive count
+// followed by a unit.
+const PeriodPattern = `^(\d+)(min|h|d|w|m|q|y)$`
+
+var (
+	ErrInvalidPeriod = errors.New("invalid kline period")
+	ErrIncompatible  = errors.New("period can not be resampled")
+	periodPattern    = regexp.MustCompile(PeriodPattern)
+	suffixUnits      = map[string]struct {
+		unit Unit
+		mul  int
+	}{
+		"min": {Minute, 1},
+		"h":   {Minute, 60},
+		"d":   {Day, 1},
+		"w":   {Week, 1},
+		"m":   {Month, 1},
+		"q":   {Month, 3},
+		"y":   {Month, 12},
+	}
+)
+
+// ParsePeriod parses a period using the suffixes of spiders.Type, where "m" is a month
+// and "min" a minute.
+func ParsePeriod(s string) (Period, error) {
+	match := periodPattern.FindStringSubmatch(s)
+	if match == nil {
+		return Period{}, fmt.Errorf("%w [%s]", ErrInvalidPeriod, s)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n <= 0 {
+		return Period{}, fmt.Errorf("%w [%s]", ErrInvalidPeriod, s)
+	}
+	u := suffixUnits[match[2]]
+	return Period{N: n * u.mul, Unit: u.unit}, nil
+}
+
+func MustParsePeriod(s string) Period {
+	p, err := ParsePeriod(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p Period) String() string {
+	switch p.Unit {
+	case Minute:
+		if p.N%60 == 0 {
+			return fmt.Sprintf("%dh", p.N/60)
+		}
+		return fmt.Sprintf("%dmin", p.N)
+	case Day:
+		return fmt.Sprintf("%dd", p.N)
+	case Week:
+		return fmt.Sprintf("%dw", p.N)
+	default:
+		if p.N%12 == 0 {
+			return fmt.Sprintf("%dy", p.N/12)
+		}
+		if p.N%3 == 0 {
+			return fmt.Sprintf("%dq", p.N/3)
+		}
+		return fmt.Sprintf("%dm", p.N)
+	}
+}
+
+// Type returns the spiders.Type for the period, which is only a provider type when
+// spiders.Type.Valid reports so.
+func (p Period) Type() spiders.Type {
+	return spiders.Type(p.String())
+}
+
+func (p Period) Intraday() bool {
+	return p.Unit == Minute
+}
+
+// CanResample reports whether candles of period from aggregate exactly into candles of period to.
+func CanResample(from, to Period) bool {
+	if from == to {
+		return true
+	}
+	switch from.Unit {
+	case Minute:
+		if to.Unit == Minute {
+			return to.N > from.N && to.N%from.N == 0
+		}
+		return true
+	case Day:
+		return from.N == 1 && (to.Unit != Minute)
+	case Week:
+		return to.Unit == Week && to.N%from.N == 0
+	default:
+		return to.Unit == Month && to.N%from.N == 0
+	}
+}
@@ -0,0 +1,138 @@
+package resample
+
+import (
+	"fmt"
+	"stock/pkg/spiders"
+	"time"
+)
+
+// Resample aggregates candles into the larger period to. Intraday buckets restart at the
+// open of every trading session of the market, so that no candle spans the lunch break
+// or two trading days, and are labeled with their scheduled end time. Daily and larger
+// buckets are labeled with the date of their last trading day. Like the close, the open
+// interest of a bucket is the one of its last candle. lines must be in time order and
+// share a single period.
+func Resample(lines []*spiders.KLine, to Period, market *spiders.Market) ([]*spiders.KLine, error) {
+	if len(lines) == 0 {
+		return make([]*spiders.KLine, 0), nil
+	}
+	from, err := ParsePeriod(string(lines[0].Type))
+	if err != nil {
+		return nil, err
+	}
+	if !CanResample(from, to) {
+		return nil, fmt.Errorf("%w from %s to %s", ErrIncompatible, from, to)
+	}
+	t := to.Type()
+	var (
+		out     = make([]*spiders.KLine, 0, len(lines))
+		current *spiders.KLine
+		lastKey bucketKey
+	)
+	for _, line := range lines {
+		lt := line.Time.In(market.Location)
+		var key bucketKey
+		var label time.Time
+		if to.Intraday() {
+			key, label = intradayBucket(lt, to, market)
+		} else {
+			date := market.TradingDay(lt)
+			key = calendarBucket(date, to)
+			label = date
+		}
+		if current == nil || key != lastKey {
+			current = &spiders.KLine{
+				Open:         line.Open,
+				Close:        line.Close,
+				High:         line.High,
+				Low:          line.Low,
+				Time:         label,
+				Type:         t,
+				OpenInterest: line.OpenInterest,
+			}
+			out = append(out, current)
+			lastKey = key
+			continue
+		}
+		current.Close = line.Close
+		current.OpenInterest = line.OpenInterest
+		if line.High > current.High {
+			current.High = line.High
+		}
+		if line.Low < current.Low {
+			current.Low = line.Low
+		}
+		if !to.Intraday() {
+			current.Time = label
+		}
+	}
+	return out, nil
+}
+
+type bucketKey struct {
+	date    time.Time
+	session int
+	index   int
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// intradayBucket places a candle, labeled with its end time, into the bucket of its
+// session it closes in. Candles outside the regular sessions fold into the nearest
+// bucket of the day, those of the night session into the night of the evening it opened.
+func intradayBucket(t time.Time, to Period, market *spiders.Market) (bucketKey, time.Time) {
+	date := truncateDay(t)
+	minute := t.Hour()*60 + t.Minute()
+	session := 0
+	for i := range market.Sessions {
+		if minute > market.Sessions[i].Open {
+			session = i
+		}
+	}
+	s := market.Sessions[session]
+	if market.SessionOf(t) == spiders.SessionNight {
+		session, s = -1, *market.Night
+		if minute < s.Open {
+			date = date.AddDate(0, 0, -1)
+			minute += 24 * 60
+		}
+	}
+	elapsed := minute - s.Open
+	if elapsed > s.Close-s.Open {
+		elapsed = s.Close - s.Open
+	}
+	index := (elapsed + to.N - 1) / to.N
+	if index < 1 {
+		index = 1
+	}
+	end := s.Open + index*to.N
+	if end > s.Close {
+		end = s.Close
+	}
+	return bucketKey{date: date, session: session, index: index}, date.Add(time.Duration(end) * time.Minute)
+}
+
+// calendarBucket groups trading days: N weekdays, or calendar weeks (starting Monday) and
+// months. Weekdays are counted from Monday 1970-01-05 so that a date falls in the same
+// bucket wherever the series starts; holidays are not known and still take their place.
+func calendarBucket(date time.Time, to Period) bucketKey {
+	// days since Monday 1970-01-05
+	days := int(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Unix()/86400) - 4
+	switch to.Unit {
+	case Day:
+		weekdays := days / 7 * 5
+		if rest := days % 7; rest < 5 {
+			weekdays += rest
+		} else {
+			weekdays += 5
+		}
+		return bucketKey{index: weekdays / to.N}
+	case Week:
+		return bucketKey{index: days / 7 / to.N}
+	default:
+		months := date.Year()*12 + int(date.Month()) - 1
+		return bucketKey{index: months / to.N}
+	}
+}
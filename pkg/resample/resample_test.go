@@ -0,0 +1,154 @@
+package resample_test
+
+import (
+	"stock/pkg/resample"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fiveMinuteDay(market *spiders.Market, date time.Time) []*spiders.KLine {
+	lines := make([]*spiders.KLine, 0)
+	price := 10.0
+	for _, s := range market.Sessions {
+		for m := s.Open + 5; m <= s.Close; m += 5 {
+			lines = append(lines, &spiders.KLine{
+				Open:  price,
+				Close: price + 0.01,
+				High:  price + 0.02,
+				Low:   price - 0.01,
+				Time:  date.Add(time.Duration(m) * time.Minute),
+				Type:  spiders.FiveMinutes,
+			})
+			price += 0.01
+		}
+	}
+	return lines
+}
+
+func TestParsePeriod(t *testing.T) {
+	for s, want := range map[string]resample.Period{
+		"5min": {N: 5, Unit: resample.Minute},
+		"2h":   {N: 120, Unit: resample.Minute},
+		"3d":   {N: 3, Unit: resample.Day},
+		"1w":   {N: 1, Unit: resample.Week},
+		"1m":   {N: 1, Unit: resample.Month},
+		"1q":   {N: 3, Unit: resample.Month},
+		"1y":   {N: 12, Unit: resample.Month},
+	} {
+		p, err := resample.ParsePeriod(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, want, p, s)
+			assert.Equal(t, s, p.String())
+		}
+	}
+	for _, s := range []string{"", "0d", "h", "2x", "1.5h"} {
+		_, err := resample.ParsePeriod(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCanResample(t *testing.T) {
+	p := resample.MustParsePeriod
+	assert.True(t, resample.CanResample(p("5min"), p("2h")))
+	assert.True(t, resample.CanResample(p("5min"), p("1w")))
+	assert.False(t, resample.CanResample(p("15min"), p("10min")))
+	assert.False(t, resample.CanResample(p("1d"), p("1h")))
+	assert.True(t, resample.CanResample(p("1m"), p("1q")))
+	assert.False(t, resample.CanResample(p("1w"), p("1m")))
+}
+
+func TestResample_Hour(t *testing.T) {
+	market := spiders.MarketOf("1.600350")
+	date := time.Date(2020, 11, 2, 0, 0, 0, 0, market.Location)
+	out, err := resample.Resample(fiveMinuteDay(market, date), resample.MustParsePeriod("1h"), market)
+	if assert.NoError(t, err) && assert.Len(t, out, 4) {
+		labels := make([]string, len(out))
+		for i := range out {
+			labels[i] = out[i].Time.Format("15:04")
+		}
+		assert.Equal(t, []string{"10:30", "11:30", "14:00", "15:00"}, labels)
+		assert.InDelta(t, 10.0, out[0].Open, 1e-9)
+		assert.InDelta(t, 10.12, out[0].Close, 1e-9)
+		assert.InDelta(t, 10.13, out[0].High, 1e-9)
+		assert.InDelta(t, 9.99, out[0].Low, 1e-9)
+		assert.Equal(t, spiders.OneHour, out[0].Type)
+	}
+}
+
+func TestResample_HongKongSessions(t *testing.T) {
+	market := spiders.MarketOf("116.00700")
+	date := time.Date(2020, 11, 2, 0, 0, 0, 0, market.Location)
+	out, err := resample.Resample(fiveMinuteDay(market, date), resample.MustParsePeriod("2h"), market)
+	if assert.NoError(t, err) {
+		labels := make([]string, len(out))
+		for i := range out {
+			labels[i] = out[i].Time.Format("15:04")
+		}
+		assert.Equal(t, []string{"11:30", "12:00", "15:00", "16:00"}, labels)
+	}
+}
+
+func TestResample_Days(t *testing.T) {
+	market := spiders.MarketOf("0.300059")
+	start := time.Date(2020, 11, 2, 0, 0, 0, 0, market.Location) // Monday
+	lines := make([]*spiders.KLine, 0)
+	for i := 0; i < 12; i++ {
+		day := start.AddDate(0, 0, i)
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		lines = append(lines, fiveMinuteDay(market, day)...)
+	}
+	daily, err := resample.Resample(lines, resample.MustParsePeriod("1d"), market)
+	if assert.NoError(t, err) && assert.Len(t, daily, 10) {
+		assert.Equal(t, start, daily[0].Time)
+	}
+	weekly, err := resample.Resample(daily, resample.MustParsePeriod("1w"), market)
+	if assert.NoError(t, err) && assert.Len(t, weekly, 2) {
+		assert.Equal(t, start.AddDate(0, 0, 4), weekly[0].Time)
+		assert.Equal(t, daily[0].Open, weekly[0].Open)
+		assert.Equal(t, daily[4].Close, weekly[0].Close)
+	}
+	threeDays, err := resample.Resample(daily, resample.MustParsePeriod("3d"), market)
+	if assert.NoError(t, err) && assert.Len(t, threeDays, 4) {
+		assert.Equal(t, start.AddDate(0, 0, 2), threeDays[0].Time)
+		assert.Equal(t, start.AddDate(0, 0, 7), threeDays[1].Time, "across the weekend")
+	}
+	// the buckets do not move with the start of the series
+	later, err := resample.Resample(daily[1:], resample.MustParsePeriod("3d"), market)
+	if assert.NoError(t, err) && assert.Len(t, later, 4) {
+		assert.Equal(t, threeDays[0].Time, later[0].Time)
+		assert.Equal(t, daily[1].Open, later[0].Open)
+		assert.Equal(t, threeDays[1:], later[1:])
+	}
+	_, err = resample.Resample(daily, resample.MustParsePeriod("1h"), market)
+	assert.Error(t, err)
+}
+
+func TestResample_NightSession(t *testing.T) {
+	market := spiders.MarketOf("113.rb2105")
+	friday := time.Date(2020, 11, 6, 0, 0, 0, 0, market.Location)
+	lines := make([]*spiders.KLine, 0)
+	for m := 21*60 + 5; m <= 23*60; m += 5 {
+		lines = append(lines, &spiders.KLine{Open: 10, Close: 10, High: 10, Low: 10, Time: friday.Add(time.Duration(m) * time.Minute), Type: spiders.FiveMinutes})
+	}
+	lines = append(lines, fiveMinuteDay(market, friday.AddDate(0, 0, 3))...)
+	for i, line := range lines {
+		line.OpenInterest = float64(1000 + i)
+	}
+	hourly, err := resample.Resample(lines, resample.MustParsePeriod("1h"), market)
+	if assert.NoError(t, err) && assert.True(t, len(hourly) > 2) {
+		assert.Equal(t, "2020-11-06 22:00", hourly[0].Time.Format("2006-01-02 15:04"))
+		assert.Equal(t, float64(1000+11), hourly[0].OpenInterest, "of the 22:00 candle")
+		assert.Equal(t, "2020-11-06 23:00", hourly[1].Time.Format("2006-01-02 15:04"))
+		assert.Equal(t, "2020-11-09 10:00", hourly[2].Time.Format("2006-01-02 15:04"))
+	}
+	daily, err := resample.Resample(lines, resample.MustParsePeriod("1d"), market)
+	if assert.NoError(t, err) && assert.Len(t, daily, 1) {
+		assert.Equal(t, friday.AddDate(0, 0, 3), daily[0].Time)
+		assert.Equal(t, lines[len(lines)-1].OpenInterest, daily[0].OpenInterest)
+	}
+}
@@ -0,0 +1,215 @@
+package spiders
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BillboardSide is the side of the seats of a 龙虎榜 entry.
+type BillboardSide string
+
+const (
+	BillboardBuy  BillboardSide = "buy"
+	BillboardSell BillboardSide = "sell"
+)
+
+// BillboardSeat is a trading seat (营业部) among the top five buyers or sellers of an
+// entry. Amounts are in yuan, ratios in percent of the turnover of the stock.
+type BillboardSeat struct {
+	Name      string        `json:"name"`
+	Side      BillboardSide `json:"side"`
+	Reason    string        `json:"reason"` // the reason of the entry the seat is listed under
+	Buy       float64       `json:"buy"`
+	BuyRatio  float64       `json:"buy_ratio"`
+	Sell      float64       `json:"sell"`
+	SellRatio float64       `json:"sell_ratio"`
+	Net       float64       `json:"net"`
+}
+
+// BillboardEntry is a stock on the daily 龙虎榜 for a reason, a stock may be listed
+// for several reasons a day. Amounts are in yuan.
+type BillboardEntry struct {
+	Date           time.Time        `json:"date" time_format:"2006-01-02"`
+	Code           string           `json:"code"` // secid
+	Name           string           `json:"name"`
+	Reason         string           `json:"reason"`         // 上榜原因
+	Interpretation string           `json:"interpretation"` // 解读, such as 主力做T
+	Close          float64          `json:"close"`
+	Gains          float64          `json:"gains"`
+	NetBuy         float64          `json:"net_buy"` // 龙虎榜净买额
+	Buy            float64          `json:"buy"`
+	Sell           float64          `json:"sell"`
+	Turnover       float64          `json:"turnover"`       // 龙虎榜成交额
+	TotalTurnover  float64          `json:"total_turnover"` // 市场总成交额
+	TurnoverRate   float64          `json:"turnover_rate"`
+	FloatValue     float64          `json:"float_value"` // 流通市值
+	Buyers         []*BillboardSeat `json:"buyers,omitempty"`
+	Sellers        []*BillboardSeat `json:"sellers,omitempty"`
+}
+
+// BillboardProvider fetches the 龙虎榜 of the A-share exchanges.
+type BillboardProvider interface {
+	// Billboard lists the entries between start and end, of code only unless it is empty,
+	// without their seats.
+	Billboard(code string, start, end time.Time) ([]*BillboardEntry, error)
+	// BillboardSeats returns the seats of all entries of code on date.
+	BillboardSeats(code string, date time.Time) ([]*BillboardSeat, error)
+}
+
+var _ BillboardProvider = new(EastMoneyProvider)
+
+// EastMoneyBillboard is a row of RPT_DAILYBILLBOARD_DETAILSNEW.
+type EastMoneyBillboard struct {
+	SecurityCode     string          `json:"SECURITY_CODE"`
+	SecuCode         string          `json:"SECUCODE"`
+	SecurityName     string          `json:"SECURITY_NAME_ABBR"`
+	TradeDate        string          `json:"TRADE_DATE"`
+	Explain          string          `json:"EXPLAIN"`
+	Explanation      string          `json:"EXPLANATION"`
+	ClosePrice       EastMoneyNumber `json:"CLOSE_PRICE"`
+	ChangeRate       EastMoneyNumber `json:"CHANGE_RATE"`
+	BillboardNetAmt  EastMoneyNumber `json:"BILLBOARD_NET_AMT"`
+	BillboardBuyAmt  EastMoneyNumber `json:"BILLBOARD_BUY_AMT"`
+	BillboardSellAmt EastMoneyNumber `json:"BILLBOARD_SELL_AMT"`
+	BillboardDealAmt EastMoneyNumber `json:"BILLBOARD_DEAL_AMT"`
+	AccumAmount      EastMoneyNumber `json:"ACCUM_AMOUNT"`
+	TurnoverRate     EastMoneyNumber `json:"TURNOVERRATE"`
+	FreeMarketCap    EastMoneyNumber `json:"FREE_MARKET_CAP"`
+}
+
+func (r *EastMoneyBillboard) ToBillboardEntry() *BillboardEntry {
+	code := secIDOfSecuCode(r.SecuCode)
+	if r.SecuCode == "" {
+		code = SecID(r.SecurityCode)
+	}
+	return &BillboardEntry{
+		Date:           parseDataCenterTime(r.TradeDate),
+		Code:           code,
+		Name:           r.SecurityName,
+		Reason:         r.Explanation,
+		Interpretation: r.Explain,
+		Close:          r.ClosePrice.Float64(),
+		Gains:          r.ChangeRate.Float64(),
+		NetBuy:         r.BillboardNetAmt.Float64(),
+		Buy:            r.BillboardBuyAmt.Float64(),
+		Sell:           r.BillboardSellAmt.Float64(),
+		Turnover:       r.BillboardDealAmt.Float64(),
+		TotalTurnover:  r.AccumAmount.Float64(),
+		TurnoverRate:   r.TurnoverRate.Float64(),
+		FloatValue:     r.FreeMarketCap.Float64(),
+	}
+}
+
+// EastMoneyBillboardSeat is a row of RPT_BILLBOARD_DAILYDETAILSBUY or SELL.
+type EastMoneyBillboardSeat struct {
+	OperateDeptName string          `json:"OPERATEDEPT_NAME"`
+	Explanation     string          `json:"EXPLANATION"`
+	Buy             EastMoneyNumber `json:"BUY"`
+	TotalBuyRio     EastMoneyNumber `json:"TOTAL_BUYRIO"`
+	Sell            EastMoneyNumber `json:"SELL"`
+	TotalSellRio    EastMoneyNumber `json:"TOTAL_SELLRIO"`
+	Net             EastMoneyNumber `json:"NET"`
+}
+
+func (r *EastMoneyBillboardSeat) ToBillboardSeat(side BillboardSide) *BillboardSeat {
+	return &BillboardSeat{
+		Name:      r.OperateDeptName,
+		Side:      side,
+		Reason:    r.Explanation,
+		Buy:       r.Buy.Float64(),
+		BuyRatio:  r.TotalBuyRio.Float64(),
+		Sell:      r.Sell.Float64(),
+		SellRatio: r.TotalSellRio.Float64(),
+		Net:       r.Net.Float64(),
+	}
+}
+
+// Billboard returns the entries by date, then by code.
+func (p *EastMoneyProvider) Billboard(code string, start, end time.Time) ([]*BillboardEntry, error) {
+	filter := fmt.Sprintf("(TRADE_DATE>='%s')(TRADE_DATE<='%s')",
+		start.In(cnMarket.Location).Format(dataCenterDateFormat), end.In(cnMarket.Location).Format(dataCenterDateFormat))
+	if code != "" {
+		secuCode, err := SecuCode(code)
+		if err != nil {
+			return nil, err
+		}
+		filter += fmt.Sprintf(`(SECUCODE="%s")`, secuCode)
+	}
+	entries := make([]*BillboardEntry, 0)
+	for page := 1; ; page++ {
+		var rows []*EastMoneyBillboard
+		pages, err := p.dataCenter(DataCenterQuery{
+			Report:   "RPT_DAILYBILLBOARD_DETAILSNEW",
+			Filter:   filter,
+			Sort:     "TRADE_DATE",
+			Page:     page,
+			PageSize: 500,
+		}, &rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			entries = append(entries, r.ToBillboardEntry())
+		}
+		if page >= pages {
+			break
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Code < entries[j].Code
+	})
+	return entries, nil
+}
+
+// BillboardSeats returns the buyers, largest first, then the sellers.
+func (p *EastMoneyProvider) BillboardSeats(code string, date time.Time) ([]*BillboardSeat, error) {
+	secuCode, err := SecuCode(code)
+	if err != nil {
+		return nil, err
+	}
+	filter := fmt.Sprintf(`(TRADE_DATE='%s')(SECUCODE="%s")`, date.In(cnMarket.Location).Format(dataCenterDateFormat), secuCode)
+	seats := make([]*BillboardSeat, 0)
+	for _, side := range []struct {
+		side   BillboardSide
+		report string
+		sort   string
+	}{
+		{BillboardBuy, "RPT_BILLBOARD_DAILYDETAILSBUY", "-BUY"},
+		{BillboardSell, "RPT_BILLBOARD_DAILYDETAILSSELL", "-SELL"},
+	} {
+		var rows []*EastMoneyBillboardSeat
+		if _, err := p.dataCenter(DataCenterQuery{
+			Report:   side.report,
+			Filter:   filter,
+			Sort:     side.sort,
+			PageSize: 50,
+		}, &rows); err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			seats = append(seats, r.ToBillboardSeat(side.side))
+		}
+	}
+	return seats, nil
+}
+
+// AttachSeats sorts the seats into the buyers and sellers of the entries of their reason.
+// Seats listed without a reason go to every entry of the stock.
+func AttachSeats(entries []*BillboardEntry, seats []*BillboardSeat) {
+	for _, e := range entries {
+		for _, s := range seats {
+			if s.Reason != "" && s.Reason != e.Reason {
+				continue
+			}
+			if s.Side == BillboardBuy {
+				e.Buyers = append(e.Buyers, s)
+			} else {
+				e.Sellers = append(e.Sellers, s)
+			}
+		}
+	}
+}
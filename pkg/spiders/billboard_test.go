@@ -0,0 +1,49 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_Billboard(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	end := time.Now()
+	data, err := spider.Billboard("", end.AddDate(0, 0, -3), end)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyBillboard_ToBillboardEntry(t *testing.T) {
+	r := new(spiders.EastMoneyBillboard)
+	data := `{"SECURITY_CODE":"600350","SECUCODE":"600350.SH","SECURITY_NAME_ABBR":"山东高速","TRADE_DATE":"2020-11-04 00:00:00",` +
+		`"EXPLAIN":"1家机构买入","EXPLANATION":"日涨幅偏离值达到7%的前5只证券","CLOSE_PRICE":6.93,"CHANGE_RATE":10.0,` +
+		`"BILLBOARD_NET_AMT":12345678.9,"BILLBOARD_BUY_AMT":50000000,"BILLBOARD_SELL_AMT":37654321.1,"BILLBOARD_DEAL_AMT":87654321.1,` +
+		`"ACCUM_AMOUNT":300000000,"TURNOVERRATE":2.5,"FREE_MARKET_CAP":null}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), r)) {
+		e := r.ToBillboardEntry()
+		assert.Equal(t, "1.600350", e.Code)
+		assert.Equal(t, "2020-11-04", e.Date.Format("2006-01-02"))
+		assert.Equal(t, "日涨幅偏离值达到7%的前5只证券", e.Reason)
+		assert.Equal(t, 12345678.9, e.NetBuy)
+		assert.Equal(t, float64(0), e.FloatValue)
+	}
+}
+
+func TestAttachSeats(t *testing.T) {
+	entries := []*spiders.BillboardEntry{{Reason: "a"}, {Reason: "b"}}
+	seats := []*spiders.BillboardSeat{
+		{Name: "机构专用", Side: spiders.BillboardBuy, Reason: "a"},
+		{Name: "华泰证券", Side: spiders.BillboardSell, Reason: "b"},
+		{Name: "中信证券", Side: spiders.BillboardBuy},
+	}
+	spiders.AttachSeats(entries, seats)
+	assert.Len(t, entries[0].Buyers, 2)
+	assert.Empty(t, entries[0].Sellers)
+	assert.Len(t, entries[1].Buyers, 1)
+	assert.Equal(t, "华泰证券", entries[1].Sellers[0].Name)
+}
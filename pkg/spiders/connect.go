@@ -0,0 +1,332 @@
+package spiders
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// connectAmountUnit converts the 百万元 of the daily Stock Connect reports into yuan.
+	connectAmountUnit = 1e6
+	// connectMinuteUnit converts the 万元 of the minute flows into yuan.
+	connectMinuteUnit = 1e4
+)
+
+// ConnectDirection is the way capital crosses Stock Connect (沪深港通).
+type ConnectDirection string
+
+const (
+	Northbound ConnectDirection = "north" // 北向, Hong Kong into Shanghai and Shenzhen
+	Southbound ConnectDirection = "south" // 南向, Shanghai and Shenzhen into Hong Kong
+)
+
+// ConnectChannel is a link of a direction, or all of them.
+type ConnectChannel string
+
+const (
+	ChannelShanghai ConnectChannel = "sh" // 沪股通, or 港股通(沪) southbound
+	ChannelShenzhen ConnectChannel = "sz" // 深股通, or 港股通(深) southbound
+	ChannelTotal    ConnectChannel = "total"
+)
+
+// connectMutualTypes are the MUTUAL_TYPE of the data center reports by direction.
+var connectMutualTypes = map[ConnectDirection]map[string]ConnectChannel{
+	Northbound: {"001": ChannelShanghai, "003": ChannelShenzhen, "005": ChannelTotal},
+	Southbound: {"002": ChannelShanghai, "004": ChannelShenzhen, "006": ChannelTotal},
+}
+
+func (d ConnectDirection) Valid() bool {
+	_, ok := connectMutualTypes[d]
+	return ok
+}
+
+// ConnectFlowPoint is the net inflow of a direction accumulated since the open up to a
+// minute, in yuan.
+type ConnectFlowPoint struct {
+	Time     time.Time `json:"time" time_format:"15:04"`
+	Shanghai float64   `json:"shanghai"`
+	Shenzhen float64   `json:"shenzhen"`
+	Total    float64   `json:"total"`
+}
+
+// ConnectIntraday is the minute series of the last trading day of a direction.
+type ConnectIntraday struct {
+	Date   time.Time           `json:"date" time_format:"2006-01-02"`
+	Points []*ConnectFlowPoint `json:"points"`
+}
+
+// ConnectFlow is the trading of a channel on a day, amounts in yuan. The figures are nil
+// on the days they were not disclosed, as the net buys since August 2024.
+type ConnectFlow struct {
+	Date         time.Time      `json:"date" time_format:"2006-01-02"`
+	Channel      ConnectChannel `json:"channel"`
+	NetBuy       *float64       `json:"net_buy"` // 成交净买额
+	Buy          *float64       `json:"buy"`
+	Sell         *float64       `json:"sell"`
+	Inflow       *float64       `json:"inflow"`        // 资金流入
+	QuotaBalance *float64       `json:"quota_balance"` // 当日余额
+	LeadStock    string         `json:"lead_stock"`    // secid of the top gainer bought, 领涨股
+	LeadName     string         `json:"lead_name"`
+	LeadGains    *float64       `json:"lead_gains"`
+	IndexClose   *float64       `json:"index_close"` // 上证指数, 深证成指 or 恒生指数
+	IndexGains   *float64       `json:"index_gains"`
+}
+
+// ConnectTopStock is one of the ten most traded stocks of a channel on a day, amounts
+// in yuan.
+type ConnectTopStock struct {
+	Date     time.Time      `json:"date" time_format:"2006-01-02"`
+	Channel  ConnectChannel `json:"channel"`
+	Rank     int            `json:"rank"`
+	Code     string         `json:"code"` // secid
+	Name     string         `json:"name"`
+	Close    float64        `json:"close"`
+	Gains    float64        `json:"gains"`
+	NetBuy   float64        `json:"net_buy"`
+	Buy      float64        `json:"buy"`
+	Sell     float64        `json:"sell"`
+	Turnover float64        `json:"turnover"` // 成交金额
+}
+
+// ConnectFlowProvider fetches the Stock Connect flows.
+type ConnectFlowProvider interface {
+	ConnectIntraday(direction ConnectDirection) (*ConnectIntraday, error)
+	ConnectHistory(direction ConnectDirection, start, end time.Time) ([]*ConnectFlow, error)
+	// ConnectTopTraded returns the top traded stocks of date, of the last day with the
+	// zero time.
+	ConnectTopTraded(direction ConnectDirection, date time.Time) ([]*ConnectTopStock, error)
+}
+
+var _ ConnectFlowProvider = new(EastMoneyProvider)
+
+// EastMoneyConnectMinutes is the result of kamt.rtmin, lines of f51 time, f52 沪 net
+// inflow, f53 沪 quota left, f54 深 net inflow, f55 深 quota left and f56 net inflow,
+// in 万元. Dates are MM-DD.
+type EastMoneyConnectMinutes struct {
+	Data *struct {
+		S2N     []string `json:"s2n"`
+		S2NDate string   `json:"s2nDate"`
+		N2S     []string `json:"n2s"`
+		N2SDate string   `json:"n2sDate"`
+	} `json:"data"`
+}
+
+// ToConnectIntraday converts the series of direction, dated in the year of now. Minutes
+// not traded yet are sent as "-" and left out.
+func (m *EastMoneyConnectMinutes) ToConnectIntraday(direction ConnectDirection, now time.Time) (*ConnectIntraday, error) {
+	intraday := &ConnectIntraday{Points: make([]*ConnectFlowPoint, 0)}
+	if m.Data == nil {
+		return intraday, nil
+	}
+	lines, date := m.Data.S2N, m.Data.S2NDate
+	if direction == Southbound {
+		lines, date = m.Data.N2S, m.Data.N2SDate
+	}
+	now = now.In(cnMarket.Location)
+	day, err := time.ParseInLocation("2006-01-02", strconv.Itoa(now.Year())+"-"+date, cnMarket.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date [%s]", date)
+	}
+	if day.After(now) {
+		// the last trading day of the previous year, read in January
+		day = day.AddDate(-1, 0, 0)
+	}
+	intraday.Date = day
+	for _, line := range lines {
+		fields := strings.Split(line, ",")
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid data line [%s]", line)
+		}
+		if fields[1] == "-" {
+			continue
+		}
+		t, err := time.ParseInLocation("15:04", fields[0], cnMarket.Location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time line [%s]", line)
+		}
+		point := &ConnectFlowPoint{
+			Time: day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute),
+		}
+		for i, v := range []*float64{&point.Shanghai, &point.Shenzhen, &point.Total} {
+			n, err := strconv.ParseFloat(fields[1+2*i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid flow line [%s]", line)
+			}
+			*v = n * connectMinuteUnit
+		}
+		intraday.Points = append(intraday.Points, point)
+	}
+	return intraday, nil
+}
+
+func (p *EastMoneyProvider) ConnectIntraday(direction ConnectDirection) (*ConnectIntraday, error) {
+	param := url.Values{}
+	param.Set("fields1", "f1,f2,f3,f4")
+	param.Set("fields2", "f51,f52,f53,f54,f55,f56")
+	var m = new(EastMoneyConnectMinutes)
+	if err := p.getJSON(fmt.Sprintf("%s%s?%s", easyMoneyAPI, "qt/kamt.rtmin/get", param.Encode()), m); err != nil {
+		return nil, err
+	}
+	return m.ToConnectIntraday(direction, time.Now())
+}
+
+// EastMoneyConnectDeal is a row of RPT_MUTUAL_DEAL_HISTORY, amounts in 百万元.
+type EastMoneyConnectDeal struct {
+	MutualType      string          `json:"MUTUAL_TYPE"`
+	TradeDate       string          `json:"TRADE_DATE"`
+	NetDealAmt      EastMoneyNumber `json:"NET_DEAL_AMT"`
+	BuyAmt          EastMoneyNumber `json:"BUY_AMT"`
+	SellAmt         EastMoneyNumber `json:"SELL_AMT"`
+	FundInflow      EastMoneyNumber `json:"FUND_INFLOW"`
+	QuotaBalance    EastMoneyNumber `json:"QUOTA_BALANCE"`
+	LeadStocksCode  string          `json:"LEAD_STOCKS_CODE"`
+	LeadStocksName  string          `json:"LEAD_STOCKS_NAME"`
+	LSChangeRate    EastMoneyNumber `json:"LS_CHANGE_RATE"`
+	IndexClosePrice EastMoneyNumber `json:"INDEX_CLOSE_PRICE"`
+	IndexChangeRate EastMoneyNumber `json:"INDEX_CHANGE_RATE"`
+}
+
+// connectAmount converts an amount in 百万元 into yuan, nil if it is not disclosed.
+func connectAmount(n EastMoneyNumber) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Value * connectAmountUnit
+	return &v
+}
+
+func (r *EastMoneyConnectDeal) ToConnectFlow(direction ConnectDirection) *ConnectFlow {
+	f := &ConnectFlow{
+		Date:         parseDataCenterTime(r.TradeDate),
+		Channel:      connectMutualTypes[direction][r.MutualType],
+		NetBuy:       connectAmount(r.NetDealAmt),
+		Buy:          connectAmount(r.BuyAmt),
+		Sell:         connectAmount(r.SellAmt),
+		Inflow:       connectAmount(r.FundInflow),
+		QuotaBalance: connectAmount(r.QuotaBalance),
+		LeadName:     r.LeadStocksName,
+		LeadGains:    r.LSChangeRate.Nullable(0),
+		IndexClose:   r.IndexClosePrice.Nullable(0),
+		IndexGains:   r.IndexChangeRate.Nullable(0),
+	}
+	if r.LeadStocksCode != "" {
+		f.LeadStock = SecID(r.LeadStocksCode)
+	}
+	return f
+}
+
+// mutualTypeFilter selects the rows of the channels of direction.
+func mutualTypeFilter(direction ConnectDirection, channels ...ConnectChannel) string {
+	types := make([]string, 0, 3)
+	for t, c := range connectMutualTypes[direction] {
+		for _, channel := range channels {
+			if c == channel {
+				types = append(types, `"`+t+`"`)
+			}
+		}
+	}
+	sort.Strings(types)
+	return fmt.Sprintf("(MUTUAL_TYPE in (%s))", strings.Join(types, ","))
+}
+
+// ConnectHistory returns the days of each channel between start and end, oldest first.
+func (p *EastMoneyProvider) ConnectHistory(direction ConnectDirection, start, end time.Time) ([]*ConnectFlow, error) {
+	filter := mutualTypeFilter(direction, ChannelShanghai, ChannelShenzhen, ChannelTotal) +
+		fmt.Sprintf("(TRADE_DATE>='%s')(TRADE_DATE<='%s')",
+			start.In(cnMarket.Location).Format(dataCenterDateFormat), end.In(cnMarket.Location).Format(dataCenterDateFormat))
+	flows := make([]*ConnectFlow, 0)
+	for page := 1; ; page++ {
+		var rows []*EastMoneyConnectDeal
+		pages, err := p.dataCenter(DataCenterQuery{
+			Report:   "RPT_MUTUAL_DEAL_HISTORY",
+			Filter:   filter,
+			Sort:     "TRADE_DATE",
+			Page:     page,
+			PageSize: 500,
+		}, &rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			flows = append(flows, r.ToConnectFlow(direction))
+		}
+		if page >= pages {
+			return flows, nil
+		}
+	}
+}
+
+// EastMoneyConnectTop is a row of RPT_MUTUAL_TOP10DEAL, amounts in yuan.
+type EastMoneyConnectTop struct {
+	MutualType   string          `json:"MUTUAL_TYPE"`
+	TradeDate    string          `json:"TRADE_DATE"`
+	Rank         int             `json:"RANK"`
+	SecurityCode string          `json:"SECURITY_CODE"`
+	SecurityName string          `json:"SECURITY_NAME"`
+	ClosePrice   EastMoneyNumber `json:"CLOSE_PRICE"`
+	ChangeRate   EastMoneyNumber `json:"CHANGE_RATE"`
+	NetBuyAmt    EastMoneyNumber `json:"NET_BUY_AMT"`
+	BuyAmt       EastMoneyNumber `json:"BUY_AMT"`
+	SellAmt      EastMoneyNumber `json:"SELL_AMT"`
+	DealAmt      EastMoneyNumber `json:"DEAL_AMT"`
+}
+
+func (r *EastMoneyConnectTop) ToConnectTopStock(direction ConnectDirection) *ConnectTopStock {
+	return &ConnectTopStock{
+		Date:     parseDataCenterTime(r.TradeDate),
+		Channel:  connectMutualTypes[direction][r.MutualType],
+		Rank:     r.Rank,
+		Code:     SecID(r.SecurityCode),
+		Name:     r.SecurityName,
+		Close:    r.ClosePrice.Float64(),
+		Gains:    r.ChangeRate.Float64(),
+		NetBuy:   r.NetBuyAmt.Float64(),
+		Buy:      r.BuyAmt.Float64(),
+		Sell:     r.SellAmt.Float64(),
+		Turnover: r.DealAmt.Float64(),
+	}
+}
+
+// EastMoneyConnectTops are rows of RPT_MUTUAL_TOP10DEAL, the most recent first.
+type EastMoneyConnectTops []*EastMoneyConnectTop
+
+// ToConnectTopStocks keeps the rows of the day of the first one, by channel then rank.
+func (rows EastMoneyConnectTops) ToConnectTopStocks(direction ConnectDirection) []*ConnectTopStock {
+	top := make([]*ConnectTopStock, 0, len(rows))
+	for _, r := range rows {
+		if r.TradeDate != rows[0].TradeDate {
+			break
+		}
+		top = append(top, r.ToConnectTopStock(direction))
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Channel != top[j].Channel {
+			return top[i].Channel < top[j].Channel
+		}
+		return top[i].Rank < top[j].Rank
+	})
+	return top
+}
+
+func (p *EastMoneyProvider) ConnectTopTraded(direction ConnectDirection, date time.Time) ([]*ConnectTopStock, error) {
+	filter := mutualTypeFilter(direction, ChannelShanghai, ChannelShenzhen)
+	if !date.IsZero() {
+		filter += fmt.Sprintf("(TRADE_DATE='%s')", date.In(cnMarket.Location).Format(dataCenterDateFormat))
+	}
+	var rows EastMoneyConnectTops
+	// two channels of ten stocks a day, the first rows are of the last day
+	_, err := p.dataCenter(DataCenterQuery{
+		Report:   "RPT_MUTUAL_TOP10DEAL",
+		Filter:   filter,
+		Sort:     "-TRADE_DATE",
+		PageSize: 20,
+	}, &rows)
+	if err != nil {
+		return nil, err
+	}
+	return rows.ToConnectTopStocks(direction), nil
+}
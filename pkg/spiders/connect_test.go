@@ -0,0 +1,102 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_ConnectHistory(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	end := time.Now()
+	data, err := spider.ConnectHistory(spiders.Northbound, end.AddDate(0, 0, -10), end)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyConnectMinutes_ToConnectIntraday(t *testing.T) {
+	m := new(spiders.EastMoneyConnectMinutes)
+	data := `{"data":{"s2n":["9:31,5123.45,5194876.55,-1200.00,5201200.00,3923.45","9:32,6000.00,5194000.00,-800.00,5200800.00,5200.00",` +
+		`"9:33,-,-,-,-,-"],"s2nDate":"01-04","n2s":["9:01,1000.00,4199000.00,500.00,4199500.00,1500.00"],"n2sDate":"01-04"}}`
+	if !assert.NoError(t, json.Unmarshal([]byte(data), m)) {
+		return
+	}
+	market := spiders.MarketOf("1.600350")
+	intraday, err := m.ToConnectIntraday(spiders.Northbound, time.Date(2021, 1, 4, 9, 33, 0, 0, market.Location))
+	if assert.NoError(t, err) && assert.Len(t, intraday.Points, 2) {
+		assert.Equal(t, time.Date(2021, 1, 4, 9, 31, 0, 0, market.Location), intraday.Points[0].Time)
+		assert.InDelta(t, 51234500, intraday.Points[0].Shanghai, 1e-6)
+		assert.InDelta(t, -12000000, intraday.Points[0].Shenzhen, 1e-6)
+		assert.InDelta(t, 52000000, intraday.Points[1].Total, 1e-6)
+	}
+	// read on new year's day the last day is of the previous year
+	south, err := m.ToConnectIntraday(spiders.Southbound, time.Date(2021, 1, 1, 9, 0, 0, 0, market.Location))
+	if assert.NoError(t, err) && assert.Len(t, south.Points, 1) {
+		assert.Equal(t, 2020, south.Date.Year())
+		assert.InDelta(t, 15000000, south.Points[0].Total, 1e-6)
+	}
+}
+
+func TestEastMoneyConnectDeal_ToConnectFlow(t *testing.T) {
+	r := new(spiders.EastMoneyConnectDeal)
+	data := `{"MUTUAL_TYPE":"003","TRADE_DATE":"2020-11-02 00:00:00","NET_DEAL_AMT":1523.45,"BUY_AMT":30000.1,"SELL_AMT":28476.65,` +
+		`"FUND_INFLOW":1600.2,"QUOTA_BALANCE":50399.8,"LEAD_STOCKS_CODE":"300059","LEAD_STOCKS_NAME":"东方财富","LS_CHANGE_RATE":5.2,` +
+		`"INDEX_CLOSE_PRICE":13402.5,"INDEX_CHANGE_RATE":0.8}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), r)) {
+		f := r.ToConnectFlow(spiders.Northbound)
+		assert.Equal(t, spiders.ChannelShenzhen, f.Channel)
+		assert.Equal(t, "2020-11-02", f.Date.Format("2006-01-02"))
+		assert.InDelta(t, 1523.45e6, *f.NetBuy, 1e-3)
+		assert.InDelta(t, 50399.8e6, *f.QuotaBalance, 1e-3)
+		assert.Equal(t, "0.300059", f.LeadStock)
+	}
+	undisclosed := new(spiders.EastMoneyConnectDeal)
+	if assert.NoError(t, json.Unmarshal([]byte(`{"MUTUAL_TYPE":"001","TRADE_DATE":"2024-08-19 00:00:00","NET_DEAL_AMT":null,"BUY_AMT":null}`), undisclosed)) {
+		f := undisclosed.ToConnectFlow(spiders.Northbound)
+		assert.Nil(t, f.NetBuy)
+		assert.Nil(t, f.Buy)
+		out, err := json.Marshal(f)
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(out), `"net_buy":null`)
+		}
+	}
+	top := new(spiders.EastMoneyConnectTop)
+	if assert.NoError(t, json.Unmarshal([]byte(`{"MUTUAL_TYPE":"002","RANK":1,"SECURITY_CODE":"00700","SECURITY_NAME":"腾讯控股","NET_BUY_AMT":-12345678}`), top)) {
+		s := top.ToConnectTopStock(spiders.Southbound)
+		assert.Equal(t, spiders.ChannelShanghai, s.Channel)
+		assert.Equal(t, "116.00700", s.Code)
+	}
+	south := new(spiders.EastMoneyConnectDeal)
+	if assert.NoError(t, json.Unmarshal([]byte(`{"MUTUAL_TYPE":"006","TRADE_DATE":"2020-11-02 00:00:00","BUY_AMT":8000.5}`), south)) {
+		f := south.ToConnectFlow(spiders.Southbound)
+		assert.Equal(t, spiders.ChannelTotal, f.Channel)
+		assert.InDelta(t, 8000.5e6, *f.Buy, 1e-3)
+	}
+	assert.True(t, spiders.Southbound.Valid())
+	assert.False(t, spiders.ConnectDirection("east").Valid())
+}
+
+func TestEastMoneyConnectTops_ToConnectTopStocks(t *testing.T) {
+	var rows spiders.EastMoneyConnectTops
+	data := `[{"MUTUAL_TYPE":"003","TRADE_DATE":"2020-11-03 00:00:00","RANK":1,"SECURITY_CODE":"300059","DEAL_AMT":1.5e9},` +
+		`{"MUTUAL_TYPE":"001","TRADE_DATE":"2020-11-03 00:00:00","RANK":2,"SECURITY_CODE":"600519"},` +
+		`{"MUTUAL_TYPE":"001","TRADE_DATE":"2020-11-03 00:00:00","RANK":1,"SECURITY_CODE":"601318"},` +
+		`{"MUTUAL_TYPE":"001","TRADE_DATE":"2020-11-02 00:00:00","RANK":1,"SECURITY_CODE":"600036"}]`
+	if !assert.NoError(t, json.Unmarshal([]byte(data), &rows)) {
+		return
+	}
+	top := rows.ToConnectTopStocks(spiders.Northbound)
+	if assert.Len(t, top, 3) {
+		assert.Equal(t, "1.601318", top[0].Code)
+		assert.Equal(t, "1.600519", top[1].Code)
+		assert.Equal(t, spiders.ChannelShenzhen, top[2].Channel)
+		assert.Equal(t, "0.300059", top[2].Code)
+		assert.Equal(t, 1.5e9, top[2].Turnover)
+		assert.Equal(t, "2020-11-03", top[2].Date.Format("2006-01-02"))
+	}
+	assert.Empty(t, spiders.EastMoneyConnectTops(nil).ToConnectTopStocks(spiders.Northbound))
+}
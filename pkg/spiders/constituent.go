@@ -0,0 +1,202 @@
+package spiders
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	cnIndexAPI       = "http://www.cnindex.com.cn/sample-detail/detail"
+	fundPositionAPI  = "https://fundmobapi.eastmoney.com/FundMNewApi/FundMNInverstPosition"
+	cnIndexPageSize  = 500
+	cnIndexMonthForm = "2006-01"
+)
+
+var ErrUnsupportedIndex = errors.New("unsupported index")
+
+// Constituent is a member of an index, or a stock held by an ETF. Weight is the percent
+// of the index, or of the net assets of the fund as of its last report.
+type Constituent struct {
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Weight   float64 `json:"weight"`
+	Industry string  `json:"industry"`
+}
+
+// ConstituentProvider lists the members of indices and the holdings of ETFs.
+type ConstituentProvider interface {
+	IndexConstituents(code string) ([]*Constituent, error)
+}
+
+var _ ConstituentProvider = new(EastMoneyProvider)
+
+// csiIndexTypes are the TYPE filters of RPT_INDEX_TS_COMPONENT, by secid of the index.
+var csiIndexTypes = map[string]string{
+	"1.000300": "1", // 沪深300
+	"0.399300": "1",
+	"1.000016": "2", // 上证50
+	"1.000905": "3", // 中证500
+	"0.399905": "3",
+}
+
+// IsETF reports whether a secid is an exchange traded fund.
+func IsETF(secid string) bool {
+	i := strings.Index(secid, ".")
+	if i < 0 || MarketOf(secid).Type != MarketCN {
+		return false
+	}
+	code := secid[i+1:]
+	if secid[:i] == "1" {
+		return strings.HasPrefix(code, "51") || strings.HasPrefix(code, "56") || strings.HasPrefix(code, "58")
+	}
+	return strings.HasPrefix(code, "159")
+}
+
+// IndexConstituents returns the CSI indices (沪深300, 上证50, 中证500) from the East
+// Money data center, the Shenzhen indices (399xxx, such as 创业板指 0.399006) from CNI
+// and the stock holdings of ETFs.
+func (p *EastMoneyProvider) IndexConstituents(code string) ([]*Constituent, error) {
+	if t, ok := csiIndexTypes[code]; ok {
+		return p.csiConstituents(t)
+	}
+	if strings.HasPrefix(code, "0.399") {
+		return p.cniConstituents(strings.TrimPrefix(code, "0."), time.Now())
+	}
+	if IsETF(code) {
+		return p.etfHoldings(code[strings.Index(code, ".")+1:])
+	}
+	return nil, fmt.Errorf("%w [%s]", ErrUnsupportedIndex, code)
+}
+
+// EastMoneyIndexComponent is a row of RPT_INDEX_TS_COMPONENT.
+type EastMoneyIndexComponent struct {
+	SecuCode         string          `json:"SECUCODE"`
+	SecurityNameAbbr string          `json:"SECURITY_NAME_ABBR"`
+	Industry         string          `json:"INDUSTRY"`
+	Weight           EastMoneyNumber `json:"WEIGHT"`
+}
+
+func (r *EastMoneyIndexComponent) ToConstituent() *Constituent {
+	return &Constituent{
+		Code:     secIDOfSecuCode(r.SecuCode),
+		Name:     r.SecurityNameAbbr,
+		Weight:   r.Weight.Float64(),
+		Industry: r.Industry,
+	}
+}
+
+func (p *EastMoneyProvider) csiConstituents(indexType string) ([]*Constituent, error) {
+	list := make([]*Constituent, 0)
+	for page := 1; ; page++ {
+		var rows []*EastMoneyIndexComponent
+		pages, err := p.dataCenter(DataCenterQuery{
+			Report:   "RPT_INDEX_TS_COMPONENT",
+			Filter:   fmt.Sprintf(`(TYPE="%s")`, indexType),
+			Sort:     "-WEIGHT",
+			Page:     page,
+			PageSize: 500,
+		}, &rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			list = append(list, r.ToConstituent())
+		}
+		if page >= pages {
+			return list, nil
+		}
+	}
+}
+
+// CNIndexSample is the sample list of an index published by CNI.
+type CNIndexSample struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Total int `json:"total"`
+		Rows  []struct {
+			SecCode      string          `json:"seccode"`
+			SecName      string          `json:"secname"`
+			IndustryName string          `json:"industryName"`
+			Weight       EastMoneyNumber `json:"weight"`
+		} `json:"rows"`
+	} `json:"data"`
+}
+
+func (s *CNIndexSample) ToConstituents() []*Constituent {
+	list := make([]*Constituent, len(s.Data.Rows))
+	for i, r := range s.Data.Rows {
+		list[i] = &Constituent{
+			Code:     SecID(r.SecCode),
+			Name:     r.SecName,
+			Weight:   r.Weight.Float64(),
+			Industry: r.IndustryName,
+		}
+	}
+	return list
+}
+
+// cniConstituents reads the samples of the month of now, or of the previous month before
+// the new one is published.
+func (p *EastMoneyProvider) cniConstituents(code string, now time.Time) ([]*Constituent, error) {
+	// from the first of the month, AddDate normalizes March 31 minus a month to March 3
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for _, month := range []time.Time{first, first.AddDate(0, -1, 0)} {
+		param := url.Values{}
+		param.Set("indexcode", code)
+		param.Set("dateStr", month.Format(cnIndexMonthForm))
+		param.Set("pageNum", "1")
+		param.Set("rows", fmt.Sprint(cnIndexPageSize))
+		s := new(CNIndexSample)
+		if err := p.getJSON(fmt.Sprintf("%s?%s", cnIndexAPI, param.Encode()), s); err != nil {
+			return nil, err
+		}
+		if len(s.Data.Rows) > 0 {
+			return s.ToConstituents(), nil
+		}
+	}
+	return make([]*Constituent, 0), nil
+}
+
+// EastMoneyFundPosition is the latest reported stock position of a fund, JZBL is the
+// percent of the net assets.
+type EastMoneyFundPosition struct {
+	Datas struct {
+		FundStocks []struct {
+			GPDM      string          `json:"GPDM"`
+			GPJC      string          `json:"GPJC"`
+			JZBL      EastMoneyNumber `json:"JZBL"`
+			IndexName string          `json:"INDEXNAME"`
+		} `json:"fundStocks"`
+	} `json:"Datas"`
+}
+
+func (s *EastMoneyFundPosition) ToConstituents() []*Constituent {
+	list := make([]*Constituent, len(s.Datas.FundStocks))
+	for i, r := range s.Datas.FundStocks {
+		list[i] = &Constituent{
+			Code:     SecID(r.GPDM),
+			Name:     r.GPJC,
+			Weight:   r.JZBL.Float64(),
+			Industry: r.IndexName,
+		}
+	}
+	return list
+}
+
+func (p *EastMoneyProvider) etfHoldings(code string) ([]*Constituent, error) {
+	param := url.Values{}
+	param.Set("FCODE", code)
+	param.Set("deviceid", "Wap")
+	param.Set("plat", "Wap")
+	param.Set("product", "EFund")
+	param.Set("version", "2.0.0")
+	s := new(EastMoneyFundPosition)
+	if err := p.getJSON(fmt.Sprintf("%s?%s", fundPositionAPI, param.Encode()), s); err != nil {
+		return nil, err
+	}
+	return s.ToConstituents(), nil
+}
@@ -0,0 +1,48 @@
+package spiders_test
+
+import (
+	"errors"
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_IndexConstituents(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.IndexConstituents("1.000016")
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyProvider_IndexConstituentsUnsupported(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	_, err := spider.IndexConstituents("1.600350")
+	assert.True(t, errors.Is(err, spiders.ErrUnsupportedIndex))
+}
+
+func TestSecID(t *testing.T) {
+	assert.Equal(t, "1.600350", spiders.SecID("600350"))
+	assert.Equal(t, "1.510300", spiders.SecID("510300"))
+	assert.Equal(t, "0.300059", spiders.SecID("300059"))
+	assert.Equal(t, "116.00700", spiders.SecID("00700"))
+	assert.True(t, spiders.IsETF("1.510300"))
+	assert.True(t, spiders.IsETF("0.159915"))
+	assert.False(t, spiders.IsETF("0.300059"))
+}
+
+func TestEastMoneyFundPosition_ToConstituents(t *testing.T) {
+	s := new(spiders.EastMoneyFundPosition)
+	data := `{"Datas":{"fundStocks":[{"GPDM":"600519","GPJC":"贵州茅台","JZBL":"5.52","INDEXNAME":"食品饮料"},` +
+		`{"GPDM":"000858","GPJC":"五粮液","JZBL":"2.31","INDEXNAME":"食品饮料"}]},"ErrCode":0}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), s)) {
+		list := s.ToConstituents()
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, "1.600519", list[0].Code)
+			assert.InDelta(t, 5.52, list[0].Weight, 1e-9)
+			assert.Equal(t, "0.000858", list[1].Code)
+		}
+	}
+}
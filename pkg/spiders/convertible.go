@@ -0,0 +1,339 @@
+package spiders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	convertibleFields = "f12,f13,f14,f2,f3,f26,f38,f227,f229,f230,f232,f233,f234,f235,f236,f237,f238,f239,f240,f241,f242"
+	bondFaceValue     = 100
+	termsBatch        = 100
+)
+
+// ErrUnknownConvertibleBond is returned for codes that are not listed convertible bonds.
+var ErrUnknownConvertibleBond = errors.New("unknown convertible bond")
+
+// ConvertibleBond is the quote of a listed convertible bond (可转债) with its conversion
+// terms. Rates are in percent, prices per 100 face value, sizes in yuan.
+type ConvertibleBond struct {
+	Code            string    `json:"code"` // secid, such as 1.113050
+	Name            string    `json:"name"`
+	Price           float64   `json:"price"`
+	Gains           float64   `json:"gains"`
+	StockCode       string    `json:"stock_code"` // secid of the underlying stock (正股)
+	StockName       string    `json:"stock_name"`
+	StockPrice      float64   `json:"stock_price"`
+	StockGains      float64   `json:"stock_gains"`
+	ConversionPrice float64   `json:"conversion_price"`  // 转股价
+	ConversionValue float64   `json:"conversion_value"`  // 转股价值
+	PremiumRate     float64   `json:"premium_rate"`      // 转股溢价率
+	PureBondValue   float64   `json:"pure_bond_value"`   // 纯债价值
+	PureBondPremium float64   `json:"pure_bond_premium"` // 纯债溢价率
+	PutTrigger      float64   `json:"put_trigger"`       // 回售触发价
+	CallTrigger     float64   `json:"call_trigger"`      // 强赎触发价
+	RedemptionPrice float64   `json:"redemption_price"`  // 到期赎回价, the last coupon included
+	YieldToMaturity float64   `json:"yield_to_maturity"` // before tax, 0 when the terms are unknown
+	RemainingSize   float64   `json:"remaining_size"`    // 剩余规模
+	IssueSize       float64   `json:"issue_size"`        // 发行规模
+	Rating          string    `json:"rating"`            // 信用评级
+	Coupons         []float64 `json:"coupons"`           // coupon rate of each year
+	ConversionStart time.Time `json:"conversion_start"`  // 转股起始日
+	ListingDate     time.Time `json:"listing_date"`
+	ValueDate       time.Time `json:"value_date"` // 起息日
+	MaturityDate    time.Time `json:"maturity_date"`
+	RemainingYears  float64   `json:"remaining_years"`
+}
+
+// ConvertibleProvider lists the convertible bonds of the Shanghai and Shenzhen exchanges.
+type ConvertibleProvider interface {
+	ConvertibleBonds() ([]*ConvertibleBond, error)
+	ConvertibleBond(code string) (*ConvertibleBond, error)
+}
+
+var _ ConvertibleProvider = new(EastMoneyProvider)
+
+// EastMoneyConvertibleItem is a row of the convertible bond comparison list (可转债比价表),
+// requested with fltt=2 so that the numbers come as decimals.
+type EastMoneyConvertibleItem struct {
+	F2   EastMoneyNumber `json:"F2"`
+	F3   EastMoneyNumber `json:"F3"`
+	F12  string          `json:"F12"`
+	F13  int             `json:"F13"`
+	F14  string          `json:"F14"`
+	F26  EastMoneyNumber `json:"F26"`
+	F38  EastMoneyNumber `json:"F38"`
+	F227 EastMoneyNumber `json:"F227"`
+	F229 EastMoneyNumber `json:"F229"`
+	F230 EastMoneyNumber `json:"F230"`
+	F232 string          `json:"F232"`
+	F233 EastMoneyNumber `json:"F233"`
+	F234 string          `json:"F234"`
+	F235 EastMoneyNumber `json:"F235"`
+	F236 EastMoneyNumber `json:"F236"`
+	F237 EastMoneyNumber `json:"F237"`
+	F238 EastMoneyNumber `json:"F238"`
+	F239 EastMoneyNumber `json:"F239"`
+	F240 EastMoneyNumber `json:"F240"`
+	F241 EastMoneyNumber `json:"F241"`
+	F242 EastMoneyNumber `json:"F242"`
+}
+
+// f2 转债最新价 f3 涨跌幅 f26 上市日期 f38 剩余张数 f227 纯债价值 f229 正股价 f230 正股涨跌幅
+// f232 正股代码 f233 正股市场 f234 正股名称 f235 转股价 f236 转股价值 f237 转股溢价率
+// f238 纯债溢价率 f239 回售触发价 f240 强赎触发价 f241 到期赎回价 f242 开始转股日
+
+func (item *EastMoneyConvertibleItem) ToConvertibleBond() *ConvertibleBond {
+	b := &ConvertibleBond{
+		Code:            strconv.Itoa(item.F13) + "." + item.F12,
+		Name:            item.F14,
+		Price:           item.F2.Float64(),
+		Gains:           item.F3.Float64(),
+		StockName:       item.F234,
+		StockPrice:      item.F229.Float64(),
+		StockGains:      item.F230.Float64(),
+		ConversionPrice: item.F235.Float64(),
+		ConversionValue: item.F236.Float64(),
+		PremiumRate:     item.F237.Float64(),
+		PureBondValue:   item.F227.Float64(),
+		PureBondPremium: item.F238.Float64(),
+		PutTrigger:      item.F239.Float64(),
+		CallTrigger:     item.F240.Float64(),
+		RedemptionPrice: item.F241.Float64(),
+		RemainingSize:   item.F38.Float64() * bondFaceValue,
+		ConversionStart: parseYYYYMMDD(item.F242),
+		ListingDate:     parseYYYYMMDD(item.F26),
+	}
+	if item.F232 != "" && item.F233.Valid {
+		b.StockCode = strconv.Itoa(int(item.F233.Value)) + "." + item.F232
+	}
+	return b
+}
+
+// parseYYYYMMDD reads the dates clist sends as numbers such as 20200312.
+func parseYYYYMMDD(n EastMoneyNumber) time.Time {
+	if !n.Valid || n.Value <= 0 {
+		return time.Time{}
+	}
+	t, _ := time.ParseInLocation(timeFormat, strconv.FormatInt(int64(n.Value), 10), cnMarket.Location)
+	return t
+}
+
+// EastMoneyConvertibleTerms is a row of RPT_BOND_CB_LIST, the issue size is in 亿元.
+type EastMoneyConvertibleTerms struct {
+	SecurityCode        string          `json:"SECURITY_CODE"`
+	Rating              string          `json:"RATING"`
+	ActualIssueScale    EastMoneyNumber `json:"ACTUAL_ISSUE_SCALE"`
+	ValueDate           string          `json:"VALUE_DATE"`
+	ExpireDate          string          `json:"EXPIRE_DATE"`
+	InterestRateExplain string          `json:"INTEREST_RATE_EXPLAIN"`
+}
+
+var couponPattern = regexp.MustCompile(`第([一二三四五六七八九十]+)年[^0-9]*?([0-9.]+)%`)
+
+// ParseCoupons reads the yearly coupon rates of an interest rate explanation such as
+// 第一年0.30%、第二年0.50%、第三年1.00%, in the order of the years.
+func ParseCoupons(explain string) []float64 {
+	coupons := make([]float64, 0)
+	for _, m := range couponPattern.FindAllStringSubmatch(explain, -1) {
+		year := chineseNumber(m[1])
+		rate, err := strconv.ParseFloat(m[2], 64)
+		if year <= 0 || err != nil {
+			continue
+		}
+		for len(coupons) < year {
+			coupons = append(coupons, 0)
+		}
+		coupons[year-1] = rate
+	}
+	return coupons
+}
+
+// chineseNumber reads the numerals from 一 to 十九, 0 for anything else.
+func chineseNumber(s string) int {
+	const digits = "一二三四五六七八九"
+	r := []rune(s)
+	switch {
+	case len(r) == 1 && r[0] == '十':
+		return 10
+	case len(r) == 1 && strings.ContainsRune(digits, r[0]):
+		return strings.IndexRune(digits, r[0])/len("一") + 1
+	case len(r) == 2 && r[0] == '十':
+		return 10 + chineseNumber(string(r[1]))
+	}
+	return 0
+}
+
+// CashFlow is a payment of a bond per 100 face value.
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// BondCashFlows returns the payments left after at: the yearly coupons paid on the
+// anniversaries of the value date, then the redemption price, which includes the last
+// coupon, at maturity. A redemption price of 0 is taken as the face value plus the last
+// coupon.
+func BondCashFlows(valueDate, maturity time.Time, coupons []float64, redemption float64, at time.Time) []CashFlow {
+	flows := make([]CashFlow, 0)
+	if len(coupons) == 0 || !maturity.After(at) {
+		return flows
+	}
+	for year := 1; year < len(coupons); year++ {
+		date := valueDate.AddDate(year, 0, 0)
+		if date.After(at) && date.Before(maturity) {
+			flows = append(flows, CashFlow{Date: date, Amount: coupons[year-1]})
+		}
+	}
+	if redemption <= 0 {
+		redemption = bondFaceValue + coupons[len(coupons)-1]
+	}
+	return append(flows, CashFlow{Date: maturity, Amount: redemption})
+}
+
+// YieldToMaturity solves the annual yield, in percent, at which the flows are worth
+// price at time at. ok is false without flows or price.
+func YieldToMaturity(price float64, flows []CashFlow, at time.Time) (ytm float64, ok bool) {
+	if price <= 0 || len(flows) == 0 {
+		return 0, false
+	}
+	value := func(y float64) float64 {
+		var v float64
+		for _, f := range flows {
+			years := f.Date.Sub(at).Hours() / 24 / 365
+			v += f.Amount / math.Pow(1+y, years)
+		}
+		return v
+	}
+	// the value falls as the yield rises, bisect between -99% and 1000%
+	low, high := -0.99, 10.0
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if value(mid) > price {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2 * 100, true
+}
+
+// ApplyTerms fills the bond with its issue terms and the yield to maturity at at.
+func (b *ConvertibleBond) ApplyTerms(terms *EastMoneyConvertibleTerms, at time.Time) {
+	b.Rating = terms.Rating
+	b.IssueSize = terms.ActualIssueScale.Float64() * 1e8
+	b.Coupons = ParseCoupons(terms.InterestRateExplain)
+	b.ValueDate = parseDataCenterTime(terms.ValueDate)
+	b.MaturityDate = parseDataCenterTime(terms.ExpireDate)
+	if b.MaturityDate.IsZero() {
+		return
+	}
+	if b.MaturityDate.After(at) {
+		b.RemainingYears = b.MaturityDate.Sub(at).Hours() / 24 / 365
+	}
+	flows := BondCashFlows(b.ValueDate, b.MaturityDate, b.Coupons, b.RedemptionPrice, at)
+	if ytm, ok := YieldToMaturity(b.Price, flows, at); ok {
+		b.YieldToMaturity = ytm
+	}
+}
+
+// convertibleQuotes pages through the comparison list of the bonds selected by fs.
+func (p *EastMoneyProvider) convertibleQuotes(fs string) ([]*ConvertibleBond, error) {
+	bonds := make([]*ConvertibleBond, 0)
+	for page := 1; ; page++ {
+		param := url.Values{}
+		param.Set("pn", strconv.Itoa(page))
+		param.Set("pz", strconv.Itoa(scanPageSize))
+		param.Set("po", "0")
+		param.Set("fid", "f12")
+		param.Set("fltt", "2")
+		param.Set("fs", fs)
+		param.Set("fields", convertibleFields)
+		var s struct {
+			Data *struct {
+				Total int                                  `json:"total"`
+				Diff  map[string]*EastMoneyConvertibleItem `json:"diff"`
+			} `json:"data"`
+		}
+		if err := p.clistInto(param, &s); err != nil {
+			return nil, err
+		}
+		if s.Data == nil {
+			return bonds, nil
+		}
+		for _, item := range s.Data.Diff {
+			bonds = append(bonds, item.ToConvertibleBond())
+		}
+		if len(s.Data.Diff) == 0 || page*scanPageSize >= s.Data.Total {
+			sort.Slice(bonds, func(i, j int) bool { return plainCode(bonds[i].Code) < plainCode(bonds[j].Code) })
+			return bonds, nil
+		}
+	}
+}
+
+// applyTerms looks the terms of the bonds up in the data center by batches of codes.
+func (p *EastMoneyProvider) applyTerms(bonds []*ConvertibleBond) error {
+	now := time.Now()
+	byCode := make(map[string]*ConvertibleBond, len(bonds))
+	for i := 0; i < len(bonds); i += termsBatch {
+		batch := bonds[i:]
+		if len(batch) > termsBatch {
+			batch = batch[:termsBatch]
+		}
+		codes := make([]string, len(batch))
+		for j, b := range batch {
+			code := plainCode(b.Code)
+			byCode[code] = b
+			codes[j] = `"` + code + `"`
+		}
+		var rows []*EastMoneyConvertibleTerms
+		_, err := p.dataCenter(DataCenterQuery{
+			Report:   "RPT_BOND_CB_LIST",
+			Filter:   fmt.Sprintf("(SECURITY_CODE in (%s))", strings.Join(codes, ",")),
+			PageSize: termsBatch,
+		}, &rows)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if b, ok := byCode[r.SecurityCode]; ok {
+				b.ApplyTerms(r, now)
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertibleBonds lists the listed convertible bonds ordered by code.
+func (p *EastMoneyProvider) ConvertibleBonds() ([]*ConvertibleBond, error) {
+	bonds, err := p.convertibleQuotes(string(ScopeBond))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.applyTerms(bonds); err != nil {
+		return nil, err
+	}
+	return bonds, nil
+}
+
+// ConvertibleBond returns a bond by its secid, such as 1.113050.
+func (p *EastMoneyProvider) ConvertibleBond(code string) (*ConvertibleBond, error) {
+	bonds, err := p.convertibleQuotes("i:" + code)
+	if err != nil {
+		return nil, err
+	}
+	if len(bonds) == 0 || bonds[0].ConversionPrice == 0 {
+		return nil, fmt.Errorf("%w [%s]", ErrUnknownConvertibleBond, code)
+	}
+	if err := p.applyTerms(bonds[:1]); err != nil {
+		return nil, err
+	}
+	return bonds[0], nil
+}
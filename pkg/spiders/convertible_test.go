@@ -0,0 +1,61 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_ConvertibleBond(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.ConvertibleBond("1.113050")
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyConvertibleItem_ToConvertibleBond(t *testing.T) {
+	item := new(spiders.EastMoneyConvertibleItem)
+	data := `{"f2":123.45,"f3":-1.2,"f12":"113050","f13":1,"f14":"南银转债","f26":20210623,"f38":1999500,"f227":95.6,` +
+		`"f229":10.2,"f230":0.5,"f232":"601009","f233":1,"f234":"南京银行","f235":10.1,"f236":100.99,"f237":22.24,` +
+		`"f238":29.13,"f239":"-","f240":13.13,"f241":110,"f242":20211222}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), item)) {
+		b := item.ToConvertibleBond()
+		assert.Equal(t, "1.113050", b.Code)
+		assert.Equal(t, "1.601009", b.StockCode)
+		assert.Equal(t, 123.45, b.Price)
+		assert.Equal(t, 22.24, b.PremiumRate)
+		assert.Equal(t, float64(0), b.PutTrigger)
+		assert.Equal(t, float64(199950000), b.RemainingSize)
+		assert.Equal(t, "2021-12-22", b.ConversionStart.Format("2006-01-02"))
+	}
+}
+
+func TestParseCoupons(t *testing.T) {
+	coupons := spiders.ParseCoupons("第一年为0.20%、第二年为0.40%、第三年为0.70%、第四年为1.20%、第五年为1.70%、第六年为2.00%。")
+	assert.Equal(t, []float64{0.2, 0.4, 0.7, 1.2, 1.7, 2}, coupons)
+	assert.Empty(t, spiders.ParseCoupons("票面利率以发行公告为准"))
+}
+
+func TestYieldToMaturity(t *testing.T) {
+	value := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	maturity := value.AddDate(3, 0, 0)
+	at := value.AddDate(1, 0, 1)
+	flows := spiders.BondCashFlows(value, maturity, []float64{1, 2, 3}, 0, at)
+	if assert.Len(t, flows, 2) {
+		assert.Equal(t, 2.0, flows[0].Amount)
+		assert.Equal(t, 103.0, flows[1].Amount)
+	}
+
+	at = value.AddDate(1, 0, 0)
+	flows = []spiders.CashFlow{{Date: at.AddDate(1, 0, 0), Amount: 2}, {Date: at.AddDate(2, 0, 0), Amount: 102}}
+	ytm, ok := spiders.YieldToMaturity(100, flows, at)
+	if assert.True(t, ok) {
+		assert.InDelta(t, 2, ytm, 0.01)
+	}
+	_, ok = spiders.YieldToMaturity(0, flows, at)
+	assert.False(t, ok)
+}
@@ -0,0 +1,143 @@
+package spiders
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CorporateAction is a dividend and bonus share plan, or a rights issue (配股), of an
+// A-share. Amounts are per share, the zero time marks a date not announced yet.
+type CorporateAction struct {
+	ReportDate     time.Time `json:"report_date"` // 报告期 the plan distributes
+	NoticeDate     time.Time `json:"notice_date"`
+	RecordDate     time.Time `json:"record_date"` // 股权登记日
+	ExDate         time.Time `json:"ex_date"`     // 除权除息日
+	PayDate        time.Time `json:"pay_date"`
+	CashDividend   float64   `json:"cash_dividend"`   // 派息, before tax
+	BonusShares    float64   `json:"bonus_shares"`    // 送股
+	TransferShares float64   `json:"transfer_shares"` // 转增
+	RightsShares   float64   `json:"rights_shares"`   // 配股
+	RightsPrice    float64   `json:"rights_price"`    // 配股价
+	Plan           string    `json:"plan"`            // such as 10转3股派0.5元
+	Progress       string    `json:"progress"`        // such as 实施分配
+}
+
+// CorporateActionProvider fetches the dividend and rights issue history of A-shares.
+type CorporateActionProvider interface {
+	CorporateActions(code string) ([]*CorporateAction, error)
+}
+
+var _ CorporateActionProvider = new(EastMoneyProvider)
+
+// EastMoneyShareBonus is a row of RPT_SHAREBONUS_DET, ratios are per 10 shares.
+type EastMoneyShareBonus struct {
+	ReportDate       string          `json:"REPORT_DATE"`
+	PlanNoticeDate   string          `json:"PLAN_NOTICE_DATE"`
+	EquityRecordDate string          `json:"EQUITY_RECORD_DATE"`
+	ExDividendDate   string          `json:"EX_DIVIDEND_DATE"`
+	PayCashDate      string          `json:"PAY_CASH_DATE"`
+	PretaxBonusRMB   EastMoneyNumber `json:"PRETAX_BONUS_RMB"`
+	BonusRatio       EastMoneyNumber `json:"BONUS_RATIO"`
+	ITRatio          EastMoneyNumber `json:"IT_RATIO"`
+	ImplPlanProfile  string          `json:"IMPL_PLAN_PROFILE"`
+	AssignProgress   string          `json:"ASSIGN_PROGRESS"`
+}
+
+func (r *EastMoneyShareBonus) ToCorporateAction() *CorporateAction {
+	return &CorporateAction{
+		ReportDate:     parseDataCenterTime(r.ReportDate),
+		NoticeDate:     parseDataCenterTime(r.PlanNoticeDate),
+		RecordDate:     parseDataCenterTime(r.EquityRecordDate),
+		ExDate:         parseDataCenterTime(r.ExDividendDate),
+		PayDate:        parseDataCenterTime(r.PayCashDate),
+		CashDividend:   r.PretaxBonusRMB.Float64() / 10,
+		BonusShares:    r.BonusRatio.Float64() / 10,
+		TransferShares: r.ITRatio.Float64() / 10,
+		Plan:           r.ImplPlanProfile,
+		Progress:       r.AssignProgress,
+	}
+}
+
+// EastMoneyAllotment is a row of RPT_IPO_ALLOTMENT, the ratio is per 10 shares.
+type EastMoneyAllotment struct {
+	NoticeDate       string          `json:"NOTICE_DATE"`
+	EquityRecordDate string          `json:"EQUITY_RECORD_DATE"`
+	ExDividendDate   string          `json:"EX_DIVIDEND_DATE"`
+	PlacingRatio     EastMoneyNumber `json:"PLACING_RATIO"`
+	IssuePrice       EastMoneyNumber `json:"ISSUE_PRICE"`
+}
+
+func (r *EastMoneyAllotment) ToCorporateAction() *CorporateAction {
+	return &CorporateAction{
+		NoticeDate:   parseDataCenterTime(r.NoticeDate),
+		RecordDate:   parseDataCenterTime(r.EquityRecordDate),
+		ExDate:       parseDataCenterTime(r.ExDividendDate),
+		RightsShares: r.PlacingRatio.Float64() / 10,
+		RightsPrice:  r.IssuePrice.Float64(),
+		Plan:         fmt.Sprintf("10配%g股(配股价%g元)", r.PlacingRatio.Float64(), r.IssuePrice.Float64()),
+	}
+}
+
+// CorporateActions returns the dividend plans and rights issues of code, latest first.
+func (p *EastMoneyProvider) CorporateActions(code string) ([]*CorporateAction, error) {
+	secuCode, err := SecuCode(code)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := p.shareBonuses(secuCode)
+	if err != nil {
+		return nil, err
+	}
+	var rows []*EastMoneyAllotment
+	if _, err := p.dataCenter(DataCenterQuery{
+		Report:   "RPT_IPO_ALLOTMENT",
+		Filter:   fmt.Sprintf(`(SECUCODE="%s")`, secuCode),
+		Sort:     "-NOTICE_DATE",
+		PageSize: 50,
+	}, &rows); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		actions = append(actions, r.ToCorporateAction())
+	}
+	SortCorporateActions(actions)
+	return actions, nil
+}
+
+// SortCorporateActions orders actions latest first, by ex-date, or by notice date for the
+// plans not executed yet.
+func SortCorporateActions(actions []*CorporateAction) {
+	date := func(a *CorporateAction) time.Time {
+		if a.ExDate.IsZero() {
+			return a.NoticeDate
+		}
+		return a.ExDate
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return date(actions[i]).After(date(actions[j]))
+	})
+}
+
+func (p *EastMoneyProvider) shareBonuses(secuCode string) ([]*CorporateAction, error) {
+	actions := make([]*CorporateAction, 0)
+	for page := 1; ; page++ {
+		var rows []*EastMoneyShareBonus
+		pages, err := p.dataCenter(DataCenterQuery{
+			Report:   "RPT_SHAREBONUS_DET",
+			Filter:   fmt.Sprintf(`(SECUCODE="%s")`, secuCode),
+			Sort:     "-REPORT_DATE",
+			Page:     page,
+			PageSize: 50,
+		}, &rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			actions = append(actions, r.ToCorporateAction())
+		}
+		if page >= pages {
+			return actions, nil
+		}
+	}
+}
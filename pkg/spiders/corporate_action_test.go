@@ -0,0 +1,63 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_CorporateActions(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.CorporateActions("0.300059")
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyShareBonus_ToCorporateAction(t *testing.T) {
+	row := new(spiders.EastMoneyShareBonus)
+	data := `{"REPORT_DATE":"2019-12-31 00:00:00","PLAN_NOTICE_DATE":"2020-03-20 00:00:00","EQUITY_RECORD_DATE":"2020-06-11 00:00:00",` +
+		`"EX_DIVIDEND_DATE":"2020-06-12 00:00:00","PAY_CASH_DATE":null,"PRETAX_BONUS_RMB":0.7,"BONUS_RATIO":null,"IT_RATIO":4,` +
+		`"IMPL_PLAN_PROFILE":"10转4.00股派0.70元(含税)","ASSIGN_PROGRESS":"实施分配"}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), row)) {
+		a := row.ToCorporateAction()
+		assert.Equal(t, "2020-06-12", a.ExDate.Format("2006-01-02"))
+		assert.Equal(t, "2020-06-11", a.RecordDate.Format("2006-01-02"))
+		assert.True(t, a.PayDate.IsZero())
+		assert.InDelta(t, 0.07, a.CashDividend, 1e-9)
+		assert.InDelta(t, 0.4, a.TransferShares, 1e-9)
+		assert.Equal(t, float64(0), a.BonusShares)
+		assert.Equal(t, "实施分配", a.Progress)
+	}
+}
+
+func TestEastMoneyAllotment_ToCorporateAction(t *testing.T) {
+	row := new(spiders.EastMoneyAllotment)
+	data := `{"NOTICE_DATE":"2020-02-20 00:00:00","EQUITY_RECORD_DATE":"2020-03-02 00:00:00",` +
+		`"EX_DIVIDEND_DATE":"2020-03-11 00:00:00","PLACING_RATIO":3,"ISSUE_PRICE":5.5}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), row)) {
+		a := row.ToCorporateAction()
+		assert.Equal(t, "2020-03-11", a.ExDate.Format("2006-01-02"))
+		assert.InDelta(t, 0.3, a.RightsShares, 1e-9)
+		assert.Equal(t, 5.5, a.RightsPrice)
+		assert.Equal(t, "10配3股(配股价5.5元)", a.Plan)
+	}
+}
+
+func TestSortCorporateActions(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, 6, d, 0, 0, 0, 0, time.UTC)
+	}
+	actions := []*spiders.CorporateAction{
+		{ExDate: day(1)},
+		{NoticeDate: day(20)}, // not executed yet
+		{ExDate: day(10), RightsShares: 0.3},
+	}
+	spiders.SortCorporateActions(actions)
+	assert.Equal(t, day(20), actions[0].NoticeDate)
+	assert.Equal(t, day(10), actions[1].ExDate)
+	assert.Equal(t, day(1), actions[2].ExDate)
+}
@@ -0,0 +1,163 @@
+package spiders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	easyMoneyDataCenterAPI = "https://datacenter-web.eastmoney.com/api/data/v1/get"
+	dataCenterTimeFormat   = "2006-01-02 15:04:05"
+	dataCenterDateFormat   = "2006-01-02" // of the date filters
+)
+
+// ErrUnsupportedMarket is returned for data East Money only publishes for A-shares.
+var ErrUnsupportedMarket = errors.New("unsupported market")
+
+// EastMoneyDataCenter is the envelope of the data center reports, result is null when
+// the filter matches nothing.
+type EastMoneyDataCenter struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Result  *struct {
+		Pages int             `json:"pages"`
+		Count int             `json:"count"`
+		Data  json.RawMessage `json:"data"`
+	} `json:"result"`
+}
+
+// DataCenterQuery selects rows of a data center report.
+type DataCenterQuery struct {
+	Report   string
+	Filter   string // such as (SECUCODE="300059.SZ")
+	Sort     string // column, prefixed with - for descending
+	Page     int
+	PageSize int
+}
+
+// getJSON decodes the response of a GET request into v.
+func (p *EastMoneyProvider) getJSON(u string, v interface{}) error {
+	return p.getJSONWithReferer(u, "", v)
+}
+
+// getJSONWithReferer is getJSON for the APIs refusing requests without a referer.
+func (p *EastMoneyProvider) getJSONWithReferer(u, referer string, v interface{}) error {
+	if p.httpClient == nil {
+		p.httpClient = httpClient
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// dataCenter decodes the rows of the query into v, a pointer to a slice, and returns the
+// number of pages.
+func (p *EastMoneyProvider) dataCenter(q DataCenterQuery, v interface{}) (int, error) {
+	param := url.Values{}
+	param.Set("reportName", q.Report)
+	param.Set("columns", "ALL")
+	param.Set("source", "WEB")
+	param.Set("client", "WEB")
+	if q.Filter != "" {
+		param.Set("filter", q.Filter)
+	}
+	if q.Sort != "" {
+		param.Set("sortColumns", strings.TrimPrefix(q.Sort, "-"))
+		if strings.HasPrefix(q.Sort, "-") {
+			param.Set("sortTypes", "-1")
+		} else {
+			param.Set("sortTypes", "1")
+		}
+	}
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = 50
+	}
+	param.Set("pageNumber", strconv.Itoa(q.Page))
+	param.Set("pageSize", strconv.Itoa(q.PageSize))
+	var s = new(EastMoneyDataCenter)
+	if err := p.getJSON(fmt.Sprintf("%s?%s", easyMoneyDataCenterAPI, param.Encode()), s); err != nil {
+		return 0, err
+	}
+	if s.Result == nil {
+		return 0, nil
+	}
+	if err := json.Unmarshal(s.Result.Data, v); err != nil {
+		return 0, err
+	}
+	return s.Result.Pages, nil
+}
+
+// SecuCode converts a secid of an A-share into the code of the data center reports,
+// 1.600350 to 600350.SH and 0.300059 to 300059.SZ.
+func SecuCode(secid string) (string, error) {
+	i := strings.Index(secid, ".")
+	if i < 0 || MarketOf(secid).Type != MarketCN {
+		return "", fmt.Errorf("%w [%s]", ErrUnsupportedMarket, secid)
+	}
+	code := secid[i+1:]
+	switch {
+	case secid[:i] == "1":
+		return code + ".SH", nil
+	case strings.HasPrefix(code, "8"), strings.HasPrefix(code, "4"):
+		return code + ".BJ", nil
+	default:
+		return code + ".SZ", nil
+	}
+}
+
+// SecID guesses the secid of a plain code: 6 digits starting with 5, 6 or 9 trade in
+// Shanghai, other 6 digits in Shenzhen or Beijing and 5 digits in Hong Kong.
+func SecID(code string) string {
+	switch {
+	case len(code) == 5:
+		return "116." + code
+	case strings.HasPrefix(code, "5"), strings.HasPrefix(code, "6"), strings.HasPrefix(code, "9"):
+		return "1." + code
+	default:
+		return "0." + code
+	}
+}
+
+// secIDOfSecuCode converts 600350.SH back to 1.600350.
+func secIDOfSecuCode(secuCode string) string {
+	i := strings.Index(secuCode, ".")
+	if i < 0 {
+		return SecID(secuCode)
+	}
+	if secuCode[i+1:] == "SH" {
+		return "1." + secuCode[:i]
+	}
+	return "0." + secuCode[:i]
+}
+
+// parseDataCenterTime reads the date columns of the reports, sent in China time and
+// empty when unknown.
+func parseDataCenterTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(dataCenterTimeFormat, s, cnMarket.Location)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -28,6 +29,10 @@ const (
 
 type EastMoneyProvider struct {
 	httpClient *http.Client
+	mu         sync.Mutex
+	// futuresScopes are the exchanges of the futures products, learned from the first
+	// scan of all of them.
+	futuresScopes map[string]Scope
 }
 
 var _ IStock = new(EastMoneyProvider)
@@ -38,24 +43,30 @@ type EastMoneyKLine struct {
 	} `json:"data"`
 }
 
-func (p *EastMoneyProvider) getKLTFromType(t Type) string {
+func (p *EastMoneyProvider) getKLTFromType(t Type) (string, error) {
 	switch t {
+	case OneMinute:
+		return "1", nil
 	case FiveMinutes:
-		return "5"
+		return "5", nil
 	case FifteenMinutes:
-		return "15"
+		return "15", nil
 	case ThirtyMinutes:
-		return "30"
+		return "30", nil
 	case OneHour:
-		return "60"
+		return "60", nil
 	case OneDay:
-		return "101"
+		return "101", nil
 	case OneWeek:
-		return "102"
+		return "102", nil
 	case OneMonth:
-		return "103"
+		return "103", nil
+	case OneQuarter:
+		return "104", nil
+	case OneYear:
+		return "106", nil
 	default:
-		return "101"
+		return "", fmt.Errorf("%w [%s]", ErrUnsupportedType, t)
 	}
 }
 
@@ -64,11 +75,23 @@ func (p *EastMoneyProvider) KLine(stockCode string, t Type, start, end time.Time
 	if p.httpClient == nil {
 		p.httpClient = httpClient
 	}
+	klt, err := p.getKLTFromType(t)
+	if err != nil {
+		return nil, err
+	}
+	market := MarketOf(stockCode)
 	param := url.Values{}
 	param.Set("secid", stockCode)
 	param.Set("fields1", "f1,f2,f3,f4,f5")
-	param.Set("fields2", "f51,f52,f53,f54,f55,f56,f57,f58")
-	param.Set("klt", p.getKLTFromType(t))
+	columns := 8
+	if market.Type == MarketFutures {
+		// f64 is the open interest
+		param.Set("fields2", "f51,f52,f53,f54,f55,f56,f57,f58,f59,f60,f61,f62,f63,f64")
+		columns = 14
+	} else {
+		param.Set("fields2", "f51,f52,f53,f54,f55,f56,f57,f58")
+	}
+	param.Set("klt", klt)
 	param.Set("fqt", "0")
 	param.Set("beg", start.Format(timeFormat))
 	param.Set("end", end.Format(timeFormat))
@@ -94,14 +117,14 @@ func (p *EastMoneyProvider) KLine(stockCode string, t Type, start, end time.Time
 	kline := make([]*KLine, len(ed.Data.KLines))
 	for i := range ed.Data.KLines {
 		line := strings.Split(ed.Data.KLines[i], ",")
-		if len(line) != 8 {
+		if len(line) != columns {
 			return nil, fmt.Errorf("invalid data line [%s]", ed.Data.KLines[i])
 		}
 		timeLayout := kLineTimeFormat
-		if t == FifteenMinutes || t == FiveMinutes || t == ThirtyMinutes || t == OneHour {
+		if t.Intraday() {
 			timeLayout = minTimeFormat
 		}
-		klineTime, err := time.ParseInLocation(timeLayout, line[0], time.Local)
+		klineTime, err := time.ParseInLocation(timeLayout, line[0], market.Location)
 		if err != nil {
 			return nil, fmt.Errorf("invalid time line [%s]", ed.Data.KLines[i])
 		}
@@ -129,6 +152,11 @@ func (p *EastMoneyProvider) KLine(stockCode string, t Type, start, end time.Time
 			Time:  klineTime,
 			Type:  t,
 		}
+		if columns > 8 {
+			if kline[i].OpenInterest, err = strconv.ParseFloat(line[13], 64); err != nil {
+				return nil, fmt.Errorf("invalid open interest data line [%s]", ed.Data.KLines[i])
+			}
+		}
 	}
 	return kline, nil
 }
@@ -148,9 +176,14 @@ func (p *EastMoneyProvider) Trend(stockCode string, day int, showBefore bool) ([
 	param.Set("secid", stockCode)
 	param.Set("fields1", "f1,f2,f3,f4,f5,f6,f7,f8,f9,f10,f11,f12,f13")
 	param.Set("fields2", "f51,f52,f53,f54,f55,f56,f57,f58")
+	market := MarketOf(stockCode)
 	iscr := "0"
 	if showBefore {
 		iscr = "1"
+		if market.Type == MarketUS {
+			// pre-market and after-hours trading
+			param.Set("iscca", "1")
+		}
 	}
 	param.Set("iscr", iscr)
 	param.Set("ndays", strconv.Itoa(day))
@@ -180,7 +213,7 @@ func (p *EastMoneyProvider) Trend(stockCode string, day int, showBefore bool) ([
 			return nil, fmt.Errorf("invalid data line [%s]", ed.Data.Trends[i])
 		}
 		timeLayout := minTimeFormat
-		trendTime, err := time.ParseInLocation(timeLayout, line[0], time.Local)
+		trendTime, err := time.ParseInLocation(timeLayout, line[0], market.Location)
 		if err != nil {
 			return nil, fmt.Errorf("invalid time line [%s]", ed.Data.Trends[i])
 		}
@@ -194,10 +227,12 @@ func (p *EastMoneyProvider) Trend(stockCode string, day int, showBefore bool) ([
 		}
 
 		trends[i] = &Trend{
-			Time:    trendTime,
-			Price:   price,
-			Volume:  volume,
-			Incrace: (price - ed.Data.Close) / ed.Data.Close,
+			Time:       trendTime,
+			Price:      price,
+			Volume:     volume,
+			Incrace:    (price - ed.Data.Close) / ed.Data.Close,
+			Session:    market.SessionOf(trendTime),
+			TradingDay: market.TradingDay(trendTime),
 		}
 	}
 	return trends, nil
@@ -207,23 +242,50 @@ type EastMoneyStockSearch struct {
 	Data []struct {
 		Name             string `json:"Name"`
 		Code             string `json:"Code"`
+		PinYin           string `json:"PinYin"`
 		MktNum           string `json:"MktNum"`
 		SecurityTypeName string `json:"SecurityTypeName"`
 	} `json:"data"`
 }
 
-func (p *EastMoneyProvider) Search(key string) ([]*Stock, error) {
+func (ed *EastMoneyStockSearch) ToStocks() []*Stock {
+	stocks := make([]*Stock, len(ed.Data))
+	for i := range ed.Data {
+		internalCode := fmt.Sprintf("%s.%s", ed.Data[i].MktNum, ed.Data[i].Code)
+		market := MarketOf(internalCode)
+		stocks[i] = &Stock{
+			Name:         ed.Data[i].Name,
+			Code:         ed.Data[i].Code,
+			InternalCode: internalCode,
+			Type:         ed.Data[i].SecurityTypeName,
+			SecurityType: ClassifySecurity(market.Type, ed.Data[i].SecurityTypeName),
+			PinYin:       ed.Data[i].PinYin,
+			Market:       market.Type,
+			Currency:     market.Currency,
+		}
+	}
+	return stocks
+}
+
+// Search matches key against names, codes and pinyin initials. With opts.Types the first
+// maxFilteredSearch matches are filtered and paged locally, since the API does not filter.
+func (p *EastMoneyProvider) Search(key string, opts SearchOptions) ([]*Stock, error) {
 	if p.httpClient == nil {
 		p.httpClient = httpClient
 	}
+	opts = opts.normalize()
+	pageIndex, pageSize := opts.Page, opts.PageSize
+	if len(opts.Types) > 0 {
+		pageIndex, pageSize = 1, maxFilteredSearch
+	}
 	param := url.Values{}
 	param.Set("and14", fmt.Sprintf("MultiMatch/Name,Code,PinYin/%s/true", key))
 	param.Set("type", "14")
 	param.Set("appid", "el1902262")
 	param.Set("token", "CCSDCZSDCXYMYZYYSYYXSMDDSMDHHDJT")
-	param.Set("returnfields14", "Name,Code,MktNum,SecurityTypeName")
-	param.Set("pageIndex14", "1")
-	param.Set("pageSize14", "20")
+	param.Set("returnfields14", "Name,Code,PinYin,MktNum,SecurityTypeName")
+	param.Set("pageIndex14", strconv.Itoa(pageIndex))
+	param.Set("pageSize14", strconv.Itoa(pageSize))
 	u := fmt.Sprintf("%s%s?%s", easyMoneySearchAPI, "Info/Search", param.Encode())
 	req, err := http.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
@@ -240,74 +302,93 @@ func (p *EastMoneyProvider) Search(key string) ([]*Stock, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(ed.Data) == 0 {
-		return make([]*Stock, 0), nil
-	}
-	stocks := make([]*Stock, len(ed.Data))
-	for i := range ed.Data {
-		stocks[i] = &Stock{
-			Name:         ed.Data[i].Name,
-			Code:         ed.Data[i].Code,
-			InternalCode: fmt.Sprintf("%s.%s", ed.Data[i].MktNum, ed.Data[i].Code),
-			Type:         ed.Data[i].SecurityTypeName,
-		}
+	stocks := ed.ToStocks()
+	RankSearch(key, stocks)
+	if len(opts.Types) > 0 {
+		return FilterSearch(stocks, opts), nil
 	}
 	return stocks, nil
 }
 
 type EastMoneyStock struct {
 	Data struct {
-		F43  int64   `json:"F43"`
-		F44  int64   `json:"F44"`
-		F45  int64   `json:"F45"`
-		F46  int64   `json:"F46"`
-		F47  int64   `json:"F47"`
-		F48  float64 `json:"F48"`
-		F50  int64   `json:"F50"`
-		F51  int64   `json:"F51"`
-		F52  int64   `json:"F52"`
-		F57  string  `json:"F57"`
-		F58  string  `json:"F58"`
-		F60  int64   `json:"F60"`
-		F107 int     `json:"F107"`
-		F117 float64 `json:"F117"`
-		F116 float64 `json:"F116"`
-		F128 string  `json:"F128"`
-		F167 int64   `json:"F167"`
-		F168 int64   `json:"F168"`
+		F43  EastMoneyNumber `json:"F43"`
+		F44  EastMoneyNumber `json:"F44"`
+		F45  EastMoneyNumber `json:"F45"`
+		F46  EastMoneyNumber `json:"F46"`
+		F47  EastMoneyNumber `json:"F47"`
+		F48  EastMoneyNumber `json:"F48"`
+		F50  EastMoneyNumber `json:"F50"`
+		F51  EastMoneyNumber `json:"F51"`
+		F52  EastMoneyNumber `json:"F52"`
+		F55  EastMoneyNumber `json:"F55"`
+		F57  string          `json:"F57"`
+		F58  string          `json:"F58"`
+		F59  EastMoneyNumber `json:"F59"`
+		F60  EastMoneyNumber `json:"F60"`
+		F84  EastMoneyNumber `json:"F84"`
+		F85  EastMoneyNumber `json:"F85"`
+		F107 int             `json:"F107"`
+		F117 EastMoneyNumber `json:"F117"`
+		F116 EastMoneyNumber `json:"F116"`
+		F128 string          `json:"F128"`
+		F152 EastMoneyNumber `json:"F152"`
+		F162 EastMoneyNumber `json:"F162"`
+		F164 EastMoneyNumber `json:"F164"`
+		F167 EastMoneyNumber `json:"F167"`
+		F168 EastMoneyNumber `json:"F168"`
+		F170 EastMoneyNumber `json:"F170"`
+		F174 EastMoneyNumber `json:"F174"`
+		F175 EastMoneyNumber `json:"F175"`
 	} `json:"Data"`
 }
 
-func intToFloat64(in int64) float64 {
-	return float64(in) / 100
-}
-
-// f43 涨幅  f44 最高 f45 最低 f46 今开 f60 昨收 f47 成交量 f48 成交额 f50 量比 f51 涨停 f52 跌停 f57 code f58 name:
+// f43 最新价 f170 涨幅 f44 最高 f45 最低 f46 今开 f60 昨收 f47 成交量 f48 成交额 f50 量比 f51 涨停 f52 跌停 f57 code f58 name:
 // f117 流通值 f116 总市值 f167 市净率 f168 换手  f128 板块 f107 start
+// f55 每股收益 f84 总股本 f85 流通股本 f162 市盈(动) f164 市盈(TTM) f174 52周最高 f175 52周最低
+// f59 价格小数位数 f152 比率小数位数
 
 func (s *EastMoneyStock) ToStockWithDetail() *StockWithDetail {
-	return &StockWithDetail{
+	price := s.Data.F59.Precision()
+	ratio := s.Data.F152.Precision()
+	market := marketOfNum(s.Data.F107)
+	detail := &StockWithDetail{
 		Stock: Stock{
 			Name:         s.Data.F58,
 			Code:         s.Data.F57,
-			InternalCode: strconv.Itoa(s.Data.F107) + s.Data.F57,
+			InternalCode: strconv.Itoa(s.Data.F107) + "." + s.Data.F57,
 			Type:         s.Data.F128,
+			Market:       market.Type,
+			Currency:     market.Currency,
 		},
-		Gains:          intToFloat64(s.Data.F43),
-		High:           intToFloat64(s.Data.F44),
-		Low:            intToFloat64(s.Data.F45),
-		Open:           intToFloat64(s.Data.F46),
-		Close:          intToFloat64(s.Data.F60),
-		TrendVolume:    intToFloat64(s.Data.F47),
-		TurnoverAmount: s.Data.F48,
-		QuantityRatio:  intToFloat64(s.Data.F50),
-		LimitUp:        intToFloat64(s.Data.F51),
-		LimitDown:      intToFloat64(s.Data.F52),
-		Circulation:    s.Data.F117,
-		TotalValue:     s.Data.F116,
-		PBRatio:        intToFloat64(s.Data.F167),
-		Turnover:       s.Data.F168,
+		Price:          s.Data.F43.Nullable(price),
+		Gains:          s.Data.F170.Nullable(ratio),
+		High:           s.Data.F44.Nullable(price),
+		Low:            s.Data.F45.Nullable(price),
+		Open:           s.Data.F46.Nullable(price),
+		Close:          s.Data.F60.Scale(price),
+		TrendVolume:    s.Data.F47.Nullable(0),
+		TurnoverAmount: s.Data.F48.Nullable(0),
+		QuantityRatio:  s.Data.F50.Nullable(ratio),
+		LimitUp:        s.Data.F51.Scale(price),
+		LimitDown:      s.Data.F52.Scale(price),
+		Circulation:    s.Data.F117.Float64(),
+		TotalValue:     s.Data.F116.Float64(),
+		PBRatio:        s.Data.F167.Nullable(ratio),
+		PERatio:        s.Data.F162.Nullable(ratio),
+		PERatioTTM:     s.Data.F164.Nullable(ratio),
+		EPS:            s.Data.F55.Float64(),
+		TurnoverRate:   s.Data.F168.Nullable(ratio),
+		Turnover:       int64(s.Data.F168.Float64()),
+		TotalShares:    s.Data.F84.Float64(),
+		FloatShares:    s.Data.F85.Float64(),
+		High52Week:     s.Data.F174.Nullable(price),
+		Low52Week:      s.Data.F175.Nullable(price),
+	}
+	if !market.PriceLimit {
+		detail.LimitUp, detail.LimitDown = 0, 0
 	}
+	return detail
 }
 
 func (p *EastMoneyProvider) Stock(code string) (*StockWithDetail, error) {
@@ -316,7 +397,7 @@ func (p *EastMoneyProvider) Stock(code string) (*StockWithDetail, error) {
 	}
 	param := url.Values{}
 	param.Set("secid", code)
-	param.Set("fields", "f43,f44,f45,f46,f47,f48,f50,f51,f52,f57,f58,f60,f107,f110,f116,f117,f128,f167,f168")
+	param.Set("fields", "f43,f44,f45,f46,f47,f48,f50,f51,f52,f55,f57,f58,f59,f60,f84,f85,f107,f110,f116,f117,f128,f152,f162,f164,f167,f168,f170,f174,f175")
 	u := fmt.Sprintf("%s%s?%s", easyMoneyAPI, "qt/stock/get", param.Encode())
 	req, err := http.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
@@ -338,74 +419,117 @@ func (p *EastMoneyProvider) Stock(code string) (*StockWithDetail, error) {
 }
 
 type EastMoneyMultiStockItem struct {
-	F2  int64   `json:"F2"`
-	F3  int64   `json:"F3"`
-	F5  int64   `json:"F5"`
-	F6  float64 `json:"F6"`
-	F9  int64   `json:"F9"`
-	F12 string  `json:"F12"`
-	F13 int     `json:"F13"`
-	F14 string  `json:"F14"`
-	F15 int64   `json:"F15"`
-	F16 int64   `json:"F16"`
-	F17 int64   `json:"F17"`
-	F18 int64   `json:"F18"`
-	F20 int64   `json:"F20"`
-	F21 int64   `json:"F21"`
-	F23 int64   `json:"F23"`
+	F1   EastMoneyNumber `json:"F1"`
+	F2   EastMoneyNumber `json:"F2"`
+	F3   EastMoneyNumber `json:"F3"`
+	F5   EastMoneyNumber `json:"F5"`
+	F6   EastMoneyNumber `json:"F6"`
+	F8   EastMoneyNumber `json:"F8"`
+	F9   EastMoneyNumber `json:"F9"`
+	F12  string          `json:"F12"`
+	F13  int             `json:"F13"`
+	F14  string          `json:"F14"`
+	F15  EastMoneyNumber `json:"F15"`
+	F16  EastMoneyNumber `json:"F16"`
+	F17  EastMoneyNumber `json:"F17"`
+	F18  EastMoneyNumber `json:"F18"`
+	F20  EastMoneyNumber `json:"F20"`
+	F21  EastMoneyNumber `json:"F21"`
+	F23  EastMoneyNumber `json:"F23"`
+	F26  EastMoneyNumber `json:"F26"`
+	F28  EastMoneyNumber `json:"F28"`
+	F38  EastMoneyNumber `json:"F38"`
+	F39  EastMoneyNumber `json:"F39"`
+	F108 EastMoneyNumber `json:"F108"`
+	F112 EastMoneyNumber `json:"F112"`
+	F115 EastMoneyNumber `json:"F115"`
+	F152 EastMoneyNumber `json:"F152"`
+	F174 EastMoneyNumber `json:"F174"`
+	F175 EastMoneyNumber `json:"F175"`
+	F350 EastMoneyNumber `json:"F350"`
+	F351 EastMoneyNumber `json:"F351"`
 }
 
-// f2: now price  f3: gains f5 成交量 f6: 成交额 f9 市盈 f12: internal_code f13 market numb f14 name f15 最高 f16 最低 f17今开 f18 昨收 f20 总市值 f21 流通市值 f23 市净值
+// f1 价格小数位数 f2: now price  f3: gains f5 成交量 f6: 成交额 f8 换手率 f9 市盈(动) f12: internal_code f13 market numb f14 name f15 最高 f16 最低 f17今开 f18 昨收 f20 总市值 f21 流通市值 f23 市净值 f26 上市日期
+// f28 昨结 f38 总股本 f39 流通股本 f108 持仓量 f112 每股收益 f115 市盈(TTM) f152 比率小数位数 f350 涨停价 f351 跌停价
 // https://blog.csdn.net/qq_38704184/article/details/101292802
 
 func (ms *EastMoneyMultiStockItem) ToMultiStock() *MultiStock {
-	return &MultiStock{
+	price := ms.F1.Precision()
+	ratio := ms.F152.Precision()
+	market := marketOfNum(ms.F13)
+	m := &MultiStock{
 		Stock: Stock{
 			Name:         ms.F14,
 			Code:         ms.F12,
 			InternalCode: strconv.Itoa(ms.F13) + "." + ms.F12,
+			Market:       market.Type,
+			Currency:     market.Currency,
 		},
-		Price:          intToFloat64(ms.F2),
-		Gains:          intToFloat64(ms.F3),
-		TrendVolume:    intToFloat64(ms.F5),
-		TurnoverAmount: ms.F6,
-		High:           intToFloat64(ms.F15),
-		Low:            intToFloat64(ms.F16),
-		Open:           intToFloat64(ms.F17),
-		Close:          intToFloat64(ms.F18),
-		TotalValue:     intToFloat64(ms.F20),
-		Circulation:    intToFloat64(ms.F21),
-		PBRatio:        intToFloat64(ms.F23),
+		Price:          ms.F2.Nullable(price),
+		Gains:          ms.F3.Nullable(ratio),
+		TrendVolume:    ms.F5.Nullable(0),
+		TurnoverAmount: ms.F6.Nullable(0),
+		High:           ms.F15.Nullable(price),
+		Low:            ms.F16.Nullable(price),
+		Open:           ms.F17.Nullable(price),
+		Close:          ms.F18.Scale(price),
+		TotalValue:     ms.F20.Float64(),
+		Circulation:    ms.F21.Float64(),
+		PBRatio:        ms.F23.Nullable(ratio),
+		PERatio:        ms.F9.Nullable(ratio),
+		PERatioTTM:     ms.F115.Nullable(ratio),
+		EPS:            ms.F112.Float64(),
+		TurnoverRate:   ms.F8.Nullable(ratio),
+		TotalShares:    ms.F38.Float64(),
+		FloatShares:    ms.F39.Float64(),
+		High52Week:     ms.F174.Nullable(price),
+		Low52Week:      ms.F175.Nullable(price),
+	}
+	if market.PriceLimit {
+		m.LimitUp = ms.F350.Scale(price)
+		m.LimitDown = ms.F351.Scale(price)
+	}
+	if market.Type == MarketFutures {
+		m.OpenInterest = ms.F108.Float64()
+		m.PreSettlement = ms.F28.Scale(price)
 	}
+	return m
 }
 
 type EastMoneyMultiStock struct {
 	Data struct {
-		Diff map[string]*EastMoneyMultiStockItem `json:"diff"`
+		Total int                                 `json:"total"`
+		Diff  map[string]*EastMoneyMultiStockItem `json:"diff"`
 	} `json:"data"`
 }
 
-func (p *EastMoneyProvider) MultiStock(codes []string) ([]*MultiStock, error) {
-	if p.httpClient == nil {
-		p.httpClient = httpClient
+const multiStockFields = "f1,f2,f3,f5,f6,f8,f9,f12,f13,f14,f15,f16,f17,f18,f19,f20,f21,f22,f23,f28,f38,f39,f108,f112,f115,f152,f174,f175,f350,f351"
+
+// clist requests one page of the quote list selected by the fs filter of param, with the
+// MultiStock fields unless param sets others.
+func (p *EastMoneyProvider) clist(param url.Values) (*EastMoneyMultiStock, error) {
+	if param.Get("fields") == "" {
+		param.Set("fields", multiStockFields)
+	}
+	var s = new(EastMoneyMultiStock)
+	if err := p.clistInto(param, s); err != nil {
+		return nil, err
 	}
+	return s, nil
+}
+
+// clistInto decodes a page of the quote list into v, for the lists with fields of their own.
+func (p *EastMoneyProvider) clistInto(param url.Values, v interface{}) error {
+	return p.getJSON(fmt.Sprintf("%s%s?%s", easyMoneyAPI, "qt/clist/get", param.Encode()), v)
+}
+
+func (p *EastMoneyProvider) MultiStock(codes []string) ([]*MultiStock, error) {
 	param := url.Values{}
 	param.Set("pi", "0")
+	param.Set("pz", strconv.Itoa(len(codes)))
 	param.Set("fs", fmt.Sprintf("i:%s", strings.Join(codes, ",i:")))
-	param.Set("fields", "f2,f3,f5,f6,f9,f12,f13,f14,f15,f16,f17,f18,f19,f20,f21,f22,f23")
-	u := fmt.Sprintf("%s%s?%s", easyMoneyAPI, "qt/clist/get", param.Encode())
-	req, err := http.NewRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
-	var s = new(EastMoneyMultiStock)
-	err = decoder.Decode(s)
+	s, err := p.clist(param)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,74 @@
+package spiders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// defaultPrecision is used when a response does not carry its decimal fields (f1/f59/f152).
+const defaultPrecision = 2
+
+// EastMoneyNumber is a numeric field of the push2 APIs. Prices and ratios are sent as
+// fixed-point integers, and suspended securities report "-" instead of a number, which
+// decodes as an invalid value.
+type EastMoneyNumber struct {
+	Value float64
+	Valid bool
+}
+
+func (n *EastMoneyNumber) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "-" || string(data) == "null" {
+		*n = EastMoneyNumber{}
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid east money number [%s]", data)
+	}
+	*n = EastMoneyNumber{Value: v, Valid: true}
+	return nil
+}
+
+func (n EastMoneyNumber) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// Float64 returns the raw value, 0 if the field is a placeholder.
+func (n EastMoneyNumber) Float64() float64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Value
+}
+
+// Scale converts a fixed-point value with the given number of decimals.
+func (n EastMoneyNumber) Scale(decimals int) float64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Value / math.Pow10(decimals)
+}
+
+// Nullable is Scale keeping placeholders apart, nil if the field is "-".
+func (n EastMoneyNumber) Nullable(decimals int) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Scale(decimals)
+	return &v
+}
+
+// Precision reads the value as a decimal count such as f1 or f152.
+func (n EastMoneyNumber) Precision() int {
+	if !n.Valid {
+		return defaultPrecision
+	}
+	return int(n.Value)
+}
@@ -1,6 +1,7 @@
 package spiders_test
 
 import (
+	"errors"
 	"stock/pkg/spiders"
 	"testing"
 	"time"
@@ -25,6 +26,22 @@ func TestEastMoneyProvider_KLine(t *testing.T) {
 	}
 }
 
+func TestEastMoneyProvider_KLineUnsupportedType(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	end := time.Now()
+	_, err := spider.KLine("1.600350", spiders.Type("2d"), end.AddDate(0, 0, -10), end)
+	assert.True(t, errors.Is(err, spiders.ErrUnsupportedType))
+}
+
+func TestType_Valid(t *testing.T) {
+	for _, st := range spiders.SupportedTypes {
+		assert.True(t, st.Valid())
+	}
+	assert.False(t, spiders.Type("2d").Valid())
+	assert.True(t, spiders.OneMinute.Intraday())
+	assert.False(t, spiders.OneQuarter.Intraday())
+}
+
 func TestEastMoneyProvider_Trend(t *testing.T) {
 	spider := &spiders.EastMoneyProvider{}
 	data, err := spider.Trend("1.600350", 2, true)
@@ -36,7 +53,7 @@ func TestEastMoneyProvider_Trend(t *testing.T) {
 
 func TestEastMoneyProvider_Search(t *testing.T) {
 	spider := &spiders.EastMoneyProvider{}
-	data, err := spider.Search("600350")
+	data, err := spider.Search("600350", spiders.SearchOptions{})
 	if assert.NoError(t, err) {
 		out, _ := json.Marshal(data)
 		t.Log(string(out))
@@ -60,3 +77,106 @@ func TestEastMoneyProvider_MultiStock(t *testing.T) {
 		t.Log(string(out))
 	}
 }
+
+func TestEastMoneyMultiStockItem_ToMultiStock(t *testing.T) {
+	item := new(spiders.EastMoneyMultiStockItem)
+	data := `{"f1":3,"f2":1234,"f3":-56,"f5":873422,"f6":107654321.0,"f8":87,"f9":1523,"f12":"510300","f13":1,"f14":"沪深300ETF",` +
+		`"f15":1240,"f16":1220,"f17":1225,"f18":1240,"f20":34567890123,"f21":34567890123,"f23":"-","f38":28011000000,"f39":28011000000,"f112":"-","f115":1498,"f152":2,"f174":1580,"f175":1102,"f350":1364,"f351":1116}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), item)) {
+		ms := item.ToMultiStock()
+		assert.Equal(t, "1.510300", ms.InternalCode)
+		assert.InDelta(t, 1.234, *ms.Price, 1e-9)
+		assert.InDelta(t, -0.56, *ms.Gains, 1e-9)
+		assert.InDelta(t, 1.24, *ms.High, 1e-9)
+		assert.InDelta(t, 1.22, *ms.Low, 1e-9)
+		assert.InDelta(t, 1.225, *ms.Open, 1e-9)
+		assert.InDelta(t, 1.24, ms.Close, 1e-9)
+		assert.Equal(t, float64(873422), *ms.TrendVolume)
+		assert.Equal(t, 107654321.0, *ms.TurnoverAmount)
+		assert.Equal(t, float64(34567890123), ms.TotalValue)
+		assert.Equal(t, float64(34567890123), ms.Circulation)
+		assert.Nil(t, ms.PBRatio)
+		assert.InDelta(t, 15.23, *ms.PERatio, 1e-9)
+		assert.InDelta(t, 14.98, *ms.PERatioTTM, 1e-9)
+		assert.InDelta(t, 0.87, *ms.TurnoverRate, 1e-9)
+		assert.Equal(t, float64(28011000000), ms.TotalShares)
+		assert.Equal(t, float64(0), ms.EPS)
+		assert.InDelta(t, 1.364, ms.LimitUp, 1e-9)
+		assert.InDelta(t, 1.116, ms.LimitDown, 1e-9)
+		assert.InDelta(t, 1.58, *ms.High52Week, 1e-9)
+		assert.InDelta(t, 1.102, *ms.Low52Week, 1e-9)
+	}
+}
+
+func TestEastMoneyMultiStockItem_Suspended(t *testing.T) {
+	item := new(spiders.EastMoneyMultiStockItem)
+	data := `{"f1":2,"f2":"-","f3":"-","f5":"-","f6":"-","f9":"-","f12":"600000","f13":1,"f14":"浦发银行",` +
+		`"f15":"-","f16":"-","f17":"-","f18":1012,"f20":"-","f21":"-","f23":"-","f152":2}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), item)) {
+		assert.False(t, item.F2.Valid)
+		ms := item.ToMultiStock()
+		assert.Nil(t, ms.Price)
+		assert.Nil(t, ms.TrendVolume)
+		assert.InDelta(t, 10.12, ms.Close, 1e-9)
+		data, err := json.Marshal(ms)
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(data), `"price":null`)
+		}
+	}
+}
+
+func TestEastMoneyStock_ToStockWithDetail(t *testing.T) {
+	s := new(spiders.EastMoneyStock)
+	data := `{"data":{"f43":1795,"f44":1810,"f45":1770,"f46":1780,"f47":1234567,"f48":2215678901.0,"f50":112,` +
+		`"f51":1966,"f52":1608,"f55":0.532,"f57":"300059","f58":"东方财富","f59":2,"f60":1787,"f84":10330000000.0,` +
+		`"f85":8900000000.0,"f107":0,"f116":185432100000.0,"f117":160000000000.0,"f128":"证券","f152":2,"f162":2532,` +
+		`"f164":2710,"f167":576,"f168":103,"f170":45,"f174":3850,"f175":1612}}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), s)) {
+		detail := s.ToStockWithDetail()
+		assert.InDelta(t, 17.95, *detail.Price, 1e-9)
+		assert.InDelta(t, 0.45, *detail.Gains, 1e-9)
+		assert.InDelta(t, 18.10, *detail.High, 1e-9)
+		assert.InDelta(t, 17.87, detail.Close, 1e-9)
+		assert.InDelta(t, 19.66, detail.LimitUp, 1e-9)
+		assert.InDelta(t, 16.08, detail.LimitDown, 1e-9)
+		assert.InDelta(t, 1.12, *detail.QuantityRatio, 1e-9)
+		assert.InDelta(t, 5.76, *detail.PBRatio, 1e-9)
+		assert.Equal(t, float64(1234567), *detail.TrendVolume)
+		assert.Equal(t, 185432100000.0, detail.TotalValue)
+		assert.InDelta(t, 25.32, *detail.PERatio, 1e-9)
+		assert.InDelta(t, 27.10, *detail.PERatioTTM, 1e-9)
+		assert.InDelta(t, 0.532, detail.EPS, 1e-9)
+		assert.InDelta(t, 1.03, *detail.TurnoverRate, 1e-9)
+		assert.Equal(t, int64(103), detail.Turnover, "the former raw value")
+		assert.InDelta(t, 38.50, *detail.High52Week, 1e-9)
+		assert.InDelta(t, 16.12, *detail.Low52Week, 1e-9)
+		assert.Equal(t, 10330000000.0, detail.TotalShares)
+		assert.Equal(t, 8900000000.0, detail.FloatShares)
+		assert.Equal(t, "0.300059", detail.InternalCode)
+		assert.Equal(t, "CNY", detail.Currency)
+	}
+}
+
+func TestEastMoneyStock_HongKong(t *testing.T) {
+	s := new(spiders.EastMoneyStock)
+	data := `{"data":{"f43":590500,"f44":595000,"f51":"-","f52":"-","f57":"00700","f58":"腾讯控股","f59":3,"f60":588000,` +
+		`"f107":116,"f152":2}}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), s)) {
+		detail := s.ToStockWithDetail()
+		assert.Equal(t, "116.00700", detail.InternalCode)
+		assert.Equal(t, spiders.MarketHK, detail.Market)
+		assert.Equal(t, "HKD", detail.Currency)
+		assert.InDelta(t, 590.5, *detail.Price, 1e-9)
+		assert.Equal(t, float64(0), detail.LimitUp)
+	}
+}
+
+func TestEastMoneyNumber(t *testing.T) {
+	var n spiders.EastMoneyNumber
+	assert.NoError(t, json.Unmarshal([]byte(`"-"`), &n))
+	assert.False(t, n.Valid)
+	assert.Equal(t, 2, n.Precision())
+	assert.NoError(t, json.Unmarshal([]byte(`12345`), &n))
+	assert.Equal(t, 12.345, n.Scale(3))
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &n))
+}
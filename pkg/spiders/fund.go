@@ -0,0 +1,179 @@
+package spiders
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	fundNAVAPI      = "https://api.fund.eastmoney.com/f10/lsjz"
+	fundNAVReferer  = "https://fundf10.eastmoney.com/"
+	fundEstimateAPI = "http://fundgz.1234567.com.cn/js/%s.js"
+	fundNAVPageSize = 20
+)
+
+// ErrNoFundEstimate is returned for funds without an intraday estimate, such as money
+// market funds.
+var ErrNoFundEstimate = errors.New("no estimate for the fund")
+
+// FundNAV is the net asset value of a fund on a day, growth is in percent.
+type FundNAV struct {
+	Date           time.Time `json:"date"`
+	NAV            float64   `json:"nav"`             // 单位净值
+	AccumulatedNAV float64   `json:"accumulated_nav"` // 累计净值
+	Growth         float64   `json:"growth"`          // 日增长率(%)
+	Purchase       string    `json:"purchase"`        // 申购状态
+	Redemption     string    `json:"redemption"`      // 赎回状态
+}
+
+// FundEstimate is the intraday estimate of the NAV of a fund from its holdings.
+type FundEstimate struct {
+	Code            string    `json:"code"`
+	Name            string    `json:"name"`
+	NAVDate         time.Time `json:"nav_date"` // date of NAV, the last published one
+	NAV             float64   `json:"nav"`
+	EstimatedNAV    float64   `json:"estimated_nav"`
+	EstimatedGrowth float64   `json:"estimated_growth"` // against NAV, in percent
+	Time            time.Time `json:"time"`
+}
+
+// FundProvider fetches open-end fund values, codes are plain fund codes or the secids
+// returned by Search.
+type FundProvider interface {
+	FundNAV(code string, start, end time.Time) ([]*FundNAV, error)
+	FundEstimate(code string) (*FundEstimate, error)
+}
+
+var _ FundProvider = new(EastMoneyProvider)
+
+type EastMoneyFundNAV struct {
+	Data struct {
+		LSJZList []struct {
+			FSRQ  string          `json:"FSRQ"`
+			DWJZ  EastMoneyNumber `json:"DWJZ"`
+			LJJZ  EastMoneyNumber `json:"LJJZ"`
+			JZZZL EastMoneyNumber `json:"JZZZL"`
+			SGZT  string          `json:"SGZT"`
+			SHZT  string          `json:"SHZT"`
+		} `json:"LSJZList"`
+	} `json:"Data"`
+	ErrCode    int    `json:"ErrCode"`
+	ErrMsg     string `json:"ErrMsg"`
+	TotalCount int    `json:"TotalCount"`
+}
+
+// ToFundNAVs converts a page, which lists the latest day first.
+func (s *EastMoneyFundNAV) ToFundNAVs() []*FundNAV {
+	list := make([]*FundNAV, 0, len(s.Data.LSJZList))
+	for _, row := range s.Data.LSJZList {
+		date, err := time.ParseInLocation(kLineTimeFormat, row.FSRQ, cnMarket.Location)
+		if err != nil {
+			continue
+		}
+		list = append(list, &FundNAV{
+			Date:           date,
+			NAV:            row.DWJZ.Float64(),
+			AccumulatedNAV: row.LJJZ.Float64(),
+			Growth:         row.JZZZL.Float64(),
+			Purchase:       row.SGZT,
+			Redemption:     row.SHZT,
+		})
+	}
+	return list
+}
+
+// FundNAV returns the published values of the fund between start and end, oldest first.
+func (p *EastMoneyProvider) FundNAV(code string, start, end time.Time) ([]*FundNAV, error) {
+	navs := make([]*FundNAV, 0)
+	for page := 1; ; page++ {
+		param := url.Values{}
+		param.Set("fundCode", plainCode(code))
+		param.Set("pageIndex", strconv.Itoa(page))
+		param.Set("pageSize", strconv.Itoa(fundNAVPageSize))
+		param.Set("startDate", start.Format(kLineTimeFormat))
+		param.Set("endDate", end.Format(kLineTimeFormat))
+		s := new(EastMoneyFundNAV)
+		if err := p.getJSONWithReferer(fmt.Sprintf("%s?%s", fundNAVAPI, param.Encode()), fundNAVReferer, s); err != nil {
+			return nil, err
+		}
+		if s.ErrCode != 0 {
+			return nil, fmt.Errorf("fund nav of %s: %s", code, s.ErrMsg)
+		}
+		navs = append(navs, s.ToFundNAVs()...)
+		if len(s.Data.LSJZList) == 0 || page*fundNAVPageSize >= s.TotalCount {
+			break
+		}
+	}
+	for i, j := 0, len(navs)-1; i < j; i, j = i+1, j-1 {
+		navs[i], navs[j] = navs[j], navs[i]
+	}
+	return navs, nil
+}
+
+// EastMoneyFundEstimate is the JSONP payload of the estimate API: jsonpgz({...});
+type EastMoneyFundEstimate struct {
+	FundCode string          `json:"fundcode"`
+	Name     string          `json:"name"`
+	JZRQ     string          `json:"jzrq"`
+	DWJZ     EastMoneyNumber `json:"dwjz"`
+	GSZ      EastMoneyNumber `json:"gsz"`
+	GSZZL    EastMoneyNumber `json:"gszzl"`
+	GZTime   string          `json:"gztime"`
+}
+
+func (s *EastMoneyFundEstimate) ToFundEstimate() *FundEstimate {
+	navDate, _ := time.ParseInLocation(kLineTimeFormat, s.JZRQ, cnMarket.Location)
+	t, _ := time.ParseInLocation(minTimeFormat, s.GZTime, cnMarket.Location)
+	return &FundEstimate{
+		Code:            s.FundCode,
+		Name:            s.Name,
+		NAVDate:         navDate,
+		NAV:             s.DWJZ.Float64(),
+		EstimatedNAV:    s.GSZ.Float64(),
+		EstimatedGrowth: s.GSZZL.Float64(),
+		Time:            t,
+	}
+}
+
+// ParseFundEstimate decodes the JSONP body of the estimate API.
+func ParseFundEstimate(body []byte) (*FundEstimate, error) {
+	start, end := bytes.IndexByte(body, '('), bytes.LastIndexByte(body, ')')
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("invalid fund estimate [%s]", body)
+	}
+	if len(bytes.TrimSpace(body[start+1:end])) == 0 {
+		return nil, ErrNoFundEstimate
+	}
+	s := new(EastMoneyFundEstimate)
+	if err := json.Unmarshal(body[start+1:end], s); err != nil {
+		return nil, err
+	}
+	return s.ToFundEstimate(), nil
+}
+
+func (p *EastMoneyProvider) FundEstimate(code string) (*FundEstimate, error) {
+	if p.httpClient == nil {
+		p.httpClient = httpClient
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(fundEstimateAPI, plainCode(code)), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFundEstimate(body)
+}
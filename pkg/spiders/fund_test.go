@@ -0,0 +1,49 @@
+package spiders_test
+
+import (
+	"errors"
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_FundNAV(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.FundNAV("161725", time.Now().AddDate(0, -2, 0), time.Now())
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyFundNAV_ToFundNAVs(t *testing.T) {
+	s := new(spiders.EastMoneyFundNAV)
+	data := `{"Data":{"LSJZList":[{"FSRQ":"2020-11-02","DWJZ":"1.2340","LJJZ":"2.3450","JZZZL":"-0.52","SGZT":"开放申购","SHZT":"开放赎回"},` +
+		`{"FSRQ":"2020-10-30","DWJZ":"1.2404","LJJZ":"2.3514","JZZZL":"","SGZT":"开放申购","SHZT":"开放赎回"}]},"ErrCode":0,"TotalCount":2}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), s)) {
+		navs := s.ToFundNAVs()
+		if assert.Len(t, navs, 2) {
+			assert.Equal(t, "2020-11-02", navs[0].Date.Format("2006-01-02"))
+			assert.InDelta(t, 1.234, navs[0].NAV, 1e-9)
+			assert.InDelta(t, 2.345, navs[0].AccumulatedNAV, 1e-9)
+			assert.InDelta(t, -0.52, navs[0].Growth, 1e-9)
+			assert.Equal(t, float64(0), navs[1].Growth)
+		}
+	}
+}
+
+func TestParseFundEstimate(t *testing.T) {
+	e, err := spiders.ParseFundEstimate([]byte(`jsonpgz({"fundcode":"161725","name":"招商中证白酒指数","jzrq":"2020-10-30",` +
+		`"dwjz":"1.2340","gsz":"1.2500","gszzl":"1.30","gztime":"2020-11-02 15:00"});`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "161725", e.Code)
+		assert.InDelta(t, 1.25, e.EstimatedNAV, 1e-9)
+		assert.InDelta(t, 1.3, e.EstimatedGrowth, 1e-9)
+		assert.Equal(t, "2020-11-02 15:00", e.Time.Format("2006-01-02 15:04"))
+		assert.Equal(t, "2020-10-30", e.NAVDate.Format("2006-01-02"))
+	}
+	_, err = spiders.ParseFundEstimate([]byte(`jsonpgz();`))
+	assert.True(t, errors.Is(err, spiders.ErrNoFundEstimate))
+}
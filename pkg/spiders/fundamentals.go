@@ -0,0 +1,320 @@
+package spiders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ReportPeriod identifies a financial report, ReportType is 一季报, 中报, 三季报 or 年报.
+type ReportPeriod struct {
+	ReportDate time.Time `json:"report_date"`
+	ReportType string    `json:"report_type"`
+}
+
+// IncomeStatement amounts are cumulative from the start of the fiscal year, in yuan. The
+// items of the statements a report does not carry are nil, null in JSON.
+type IncomeStatement struct {
+	ReportPeriod
+	TotalRevenue      *float64 `json:"total_revenue"`       // 营业总收入
+	Revenue           *float64 `json:"revenue"`             // 营业收入
+	OperatingCost     *float64 `json:"operating_cost"`      // 营业成本
+	SalesExpense      *float64 `json:"sales_expense"`       // 销售费用
+	AdminExpense      *float64 `json:"admin_expense"`       // 管理费用
+	RDExpense         *float64 `json:"rd_expense"`          // 研发费用
+	FinanceExpense    *float64 `json:"finance_expense"`     // 财务费用
+	OperatingProfit   *float64 `json:"operating_profit"`    // 营业利润
+	TotalProfit       *float64 `json:"total_profit"`        // 利润总额
+	IncomeTax         *float64 `json:"income_tax"`          // 所得税
+	NetProfit         *float64 `json:"net_profit"`          // 净利润
+	ParentNetProfit   *float64 `json:"parent_net_profit"`   // 归母净利润
+	DeductedNetProfit *float64 `json:"deducted_net_profit"` // 扣非归母净利润
+	BasicEPS          *float64 `json:"basic_eps"`
+	DilutedEPS        *float64 `json:"diluted_eps"`
+}
+
+type BalanceSheet struct {
+	ReportPeriod
+	TotalAssets        *float64 `json:"total_assets"`
+	CurrentAssets      *float64 `json:"current_assets"`
+	Cash               *float64 `json:"cash"`        // 货币资金
+	Receivables        *float64 `json:"receivables"` // 应收账款
+	Inventory          *float64 `json:"inventory"`
+	TotalLiabilities   *float64 `json:"total_liabilities"`
+	CurrentLiabilities *float64 `json:"current_liabilities"`
+	ShortLoan          *float64 `json:"short_loan"`
+	LongLoan           *float64 `json:"long_loan"`
+	TotalEquity        *float64 `json:"total_equity"`
+	ParentEquity       *float64 `json:"parent_equity"` // 归母权益
+}
+
+type CashFlowStatement struct {
+	ReportPeriod
+	OperatingCashFlow *float64 `json:"operating_cash_flow"`
+	InvestingCashFlow *float64 `json:"investing_cash_flow"`
+	FinancingCashFlow *float64 `json:"financing_cash_flow"`
+	NetCashIncrease   *float64 `json:"net_cash_increase"`
+	EndingCash        *float64 `json:"ending_cash"`
+}
+
+// KeyRatios holds the per share figures in yuan and the ratios and growths in percent.
+// Like the statements, figures a report does not carry are nil.
+type KeyRatios struct {
+	ReportPeriod
+	EPS                   *float64 `json:"eps"`                      // 基本每股收益
+	DeductedEPS           *float64 `json:"deducted_eps"`             // 扣非每股收益
+	BPS                   *float64 `json:"bps"`                      // 每股净资产
+	OperatingCashPerShare *float64 `json:"operating_cash_per_share"` // 每股经营现金流
+	ROE                   *float64 `json:"roe"`                      // 加权净资产收益率
+	DeductedROE           *float64 `json:"deducted_roe"`
+	GrossMargin           *float64 `json:"gross_margin"` // 毛利率
+	NetMargin             *float64 `json:"net_margin"`   // 净利率
+	DebtRatio             *float64 `json:"debt_ratio"`   // 资产负债率
+	RevenueGrowth         *float64 `json:"revenue_growth"`
+	NetProfitGrowth       *float64 `json:"net_profit_growth"`
+	EPSGrowth             *float64 `json:"eps_growth"` // against the same period of the previous year
+}
+
+// Fundamentals lists the reports of a stock, latest first.
+type Fundamentals struct {
+	Code     string               `json:"code"`
+	Income   []*IncomeStatement   `json:"income"`
+	Balance  []*BalanceSheet      `json:"balance"`
+	CashFlow []*CashFlowStatement `json:"cash_flow"`
+	Ratios   []*KeyRatios         `json:"ratios"`
+}
+
+// ErrUnsupportedCompany is returned for the banks, brokers and insurers, whose statements
+// follow other templates than the one of non-financial companies.
+var ErrUnsupportedCompany = errors.New("unsupported company type")
+
+// FundamentalsProvider fetches the financial reports of A-shares.
+type FundamentalsProvider interface {
+	Fundamentals(code string, periods int) (*Fundamentals, error)
+}
+
+var _ FundamentalsProvider = new(EastMoneyProvider)
+
+type EastMoneyReportPeriod struct {
+	ReportDate string `json:"REPORT_DATE"`
+	ReportType string `json:"REPORT_TYPE"`
+}
+
+func (r EastMoneyReportPeriod) ToReportPeriod() ReportPeriod {
+	return ReportPeriod{
+		ReportDate: parseDataCenterTime(r.ReportDate),
+		ReportType: r.ReportType,
+	}
+}
+
+// EastMoneyIncome is a row of RPT_F10_FINANCE_GINCOME, the template of non-financial companies.
+type EastMoneyIncome struct {
+	EastMoneyReportPeriod
+	TotalOperateIncome    EastMoneyNumber `json:"TOTAL_OPERATE_INCOME"`
+	OperateIncome         EastMoneyNumber `json:"OPERATE_INCOME"`
+	OperateCost           EastMoneyNumber `json:"OPERATE_COST"`
+	SaleExpense           EastMoneyNumber `json:"SALE_EXPENSE"`
+	ManageExpense         EastMoneyNumber `json:"MANAGE_EXPENSE"`
+	ResearchExpense       EastMoneyNumber `json:"RESEARCH_EXPENSE"`
+	FinanceExpense        EastMoneyNumber `json:"FINANCE_EXPENSE"`
+	OperateProfit         EastMoneyNumber `json:"OPERATE_PROFIT"`
+	TotalProfit           EastMoneyNumber `json:"TOTAL_PROFIT"`
+	IncomeTax             EastMoneyNumber `json:"INCOME_TAX"`
+	NetProfit             EastMoneyNumber `json:"NETPROFIT"`
+	ParentNetProfit       EastMoneyNumber `json:"PARENT_NETPROFIT"`
+	DeductParentNetProfit EastMoneyNumber `json:"DEDUCT_PARENT_NETPROFIT"`
+	BasicEPS              EastMoneyNumber `json:"BASIC_EPS"`
+	DilutedEPS            EastMoneyNumber `json:"DILUTED_EPS"`
+}
+
+func (r *EastMoneyIncome) ToIncomeStatement() *IncomeStatement {
+	return &IncomeStatement{
+		ReportPeriod:      r.ToReportPeriod(),
+		TotalRevenue:      r.TotalOperateIncome.Nullable(0),
+		Revenue:           r.OperateIncome.Nullable(0),
+		OperatingCost:     r.OperateCost.Nullable(0),
+		SalesExpense:      r.SaleExpense.Nullable(0),
+		AdminExpense:      r.ManageExpense.Nullable(0),
+		RDExpense:         r.ResearchExpense.Nullable(0),
+		FinanceExpense:    r.FinanceExpense.Nullable(0),
+		OperatingProfit:   r.OperateProfit.Nullable(0),
+		TotalProfit:       r.TotalProfit.Nullable(0),
+		IncomeTax:         r.IncomeTax.Nullable(0),
+		NetProfit:         r.NetProfit.Nullable(0),
+		ParentNetProfit:   r.ParentNetProfit.Nullable(0),
+		DeductedNetProfit: r.DeductParentNetProfit.Nullable(0),
+		BasicEPS:          r.BasicEPS.Nullable(0),
+		DilutedEPS:        r.DilutedEPS.Nullable(0),
+	}
+}
+
+// EastMoneyBalance is a row of RPT_F10_FINANCE_GBALANCE.
+type EastMoneyBalance struct {
+	EastMoneyReportPeriod
+	TotalAssets        EastMoneyNumber `json:"TOTAL_ASSETS"`
+	TotalCurrentAssets EastMoneyNumber `json:"TOTAL_CURRENT_ASSETS"`
+	MonetaryFunds      EastMoneyNumber `json:"MONETARYFUNDS"`
+	AccountsRece       EastMoneyNumber `json:"ACCOUNTS_RECE"`
+	Inventory          EastMoneyNumber `json:"INVENTORY"`
+	TotalLiabilities   EastMoneyNumber `json:"TOTAL_LIABILITIES"`
+	TotalCurrentLiab   EastMoneyNumber `json:"TOTAL_CURRENT_LIAB"`
+	ShortLoan          EastMoneyNumber `json:"SHORT_LOAN"`
+	LongLoan           EastMoneyNumber `json:"LONG_LOAN"`
+	TotalEquity        EastMoneyNumber `json:"TOTAL_EQUITY"`
+	TotalParentEquity  EastMoneyNumber `json:"TOTAL_PARENT_EQUITY"`
+}
+
+func (r *EastMoneyBalance) ToBalanceSheet() *BalanceSheet {
+	return &BalanceSheet{
+		ReportPeriod:       r.ToReportPeriod(),
+		TotalAssets:        r.TotalAssets.Nullable(0),
+		CurrentAssets:      r.TotalCurrentAssets.Nullable(0),
+		Cash:               r.MonetaryFunds.Nullable(0),
+		Receivables:        r.AccountsRece.Nullable(0),
+		Inventory:          r.Inventory.Nullable(0),
+		TotalLiabilities:   r.TotalLiabilities.Nullable(0),
+		CurrentLiabilities: r.TotalCurrentLiab.Nullable(0),
+		ShortLoan:          r.ShortLoan.Nullable(0),
+		LongLoan:           r.LongLoan.Nullable(0),
+		TotalEquity:        r.TotalEquity.Nullable(0),
+		ParentEquity:       r.TotalParentEquity.Nullable(0),
+	}
+}
+
+// EastMoneyCashFlow is a row of RPT_F10_FINANCE_GCASHFLOW.
+type EastMoneyCashFlow struct {
+	EastMoneyReportPeriod
+	NetCashOperate EastMoneyNumber `json:"NETCASH_OPERATE"`
+	NetCashInvest  EastMoneyNumber `json:"NETCASH_INVEST"`
+	NetCashFinance EastMoneyNumber `json:"NETCASH_FINANCE"`
+	CCEAdd         EastMoneyNumber `json:"CCE_ADD"`
+	EndCCE         EastMoneyNumber `json:"END_CCE"`
+}
+
+func (r *EastMoneyCashFlow) ToCashFlowStatement() *CashFlowStatement {
+	return &CashFlowStatement{
+		ReportPeriod:      r.ToReportPeriod(),
+		OperatingCashFlow: r.NetCashOperate.Nullable(0),
+		InvestingCashFlow: r.NetCashInvest.Nullable(0),
+		FinancingCashFlow: r.NetCashFinance.Nullable(0),
+		NetCashIncrease:   r.CCEAdd.Nullable(0),
+		EndingCash:        r.EndCCE.Nullable(0),
+	}
+}
+
+// EastMoneyMainFinance is a row of RPT_F10_FINANCE_MAINFINADATA.
+type EastMoneyMainFinance struct {
+	EastMoneyReportPeriod
+	EPSJB              EastMoneyNumber `json:"EPSJB"`
+	EPSKCJB            EastMoneyNumber `json:"EPSKCJB"`
+	BPS                EastMoneyNumber `json:"BPS"`
+	MGJYXJJE           EastMoneyNumber `json:"MGJYXJJE"`
+	ROEJQ              EastMoneyNumber `json:"ROEJQ"`
+	ROEKCJQ            EastMoneyNumber `json:"ROEKCJQ"`
+	XSMLL              EastMoneyNumber `json:"XSMLL"`
+	XSJLL              EastMoneyNumber `json:"XSJLL"`
+	ZCFZL              EastMoneyNumber `json:"ZCFZL"`
+	TotalOperateReveTZ EastMoneyNumber `json:"TOTALOPERATEREVETZ"`
+	ParentNetProfitTZ  EastMoneyNumber `json:"PARENTNETPROFITTZ"`
+}
+
+func (r *EastMoneyMainFinance) ToKeyRatios() *KeyRatios {
+	return &KeyRatios{
+		ReportPeriod:          r.ToReportPeriod(),
+		EPS:                   r.EPSJB.Nullable(0),
+		DeductedEPS:           r.EPSKCJB.Nullable(0),
+		BPS:                   r.BPS.Nullable(0),
+		OperatingCashPerShare: r.MGJYXJJE.Nullable(0),
+		ROE:                   r.ROEJQ.Nullable(0),
+		DeductedROE:           r.ROEKCJQ.Nullable(0),
+		GrossMargin:           r.XSMLL.Nullable(0),
+		NetMargin:             r.XSJLL.Nullable(0),
+		DebtRatio:             r.ZCFZL.Nullable(0),
+		RevenueGrowth:         r.TotalOperateReveTZ.Nullable(0),
+		NetProfitGrowth:       r.ParentNetProfitTZ.Nullable(0),
+	}
+}
+
+// FillEPSGrowth sets the EPS growth of each period against the same period a year before
+// found in ratios.
+func FillEPSGrowth(ratios []*KeyRatios) {
+	byDate := make(map[string]*KeyRatios, len(ratios))
+	for _, r := range ratios {
+		byDate[r.ReportDate.Format(kLineTimeFormat)] = r
+	}
+	for _, r := range ratios {
+		prev, ok := byDate[r.ReportDate.AddDate(-1, 0, 0).Format(kLineTimeFormat)]
+		if !ok || r.EPS == nil || prev.EPS == nil || *prev.EPS == 0 {
+			continue
+		}
+		growth := (*r.EPS - *prev.EPS) / math.Abs(*prev.EPS) * 100
+		r.EPSGrowth = &growth
+	}
+}
+
+// Fundamentals returns the last periods reports of a non-financial A-share, and
+// ErrUnsupportedCompany for financial ones.
+func (p *EastMoneyProvider) Fundamentals(code string, periods int) (*Fundamentals, error) {
+	secuCode, err := SecuCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if periods <= 0 {
+		periods = 8
+	}
+	query := func(report string, size int) DataCenterQuery {
+		return DataCenterQuery{
+			Report:   report,
+			Filter:   fmt.Sprintf(`(SECUCODE="%s")`, secuCode),
+			Sort:     "-REPORT_DATE",
+			PageSize: size,
+		}
+	}
+	var (
+		income   []*EastMoneyIncome
+		balance  []*EastMoneyBalance
+		cashFlow []*EastMoneyCashFlow
+		main     []*EastMoneyMainFinance
+	)
+	if _, err := p.dataCenter(query("RPT_F10_FINANCE_GINCOME", periods), &income); err != nil {
+		return nil, err
+	}
+	if _, err := p.dataCenter(query("RPT_F10_FINANCE_GBALANCE", periods), &balance); err != nil {
+		return nil, err
+	}
+	if _, err := p.dataCenter(query("RPT_F10_FINANCE_GCASHFLOW", periods), &cashFlow); err != nil {
+		return nil, err
+	}
+	// four more quarters for the growth of the oldest period
+	if _, err := p.dataCenter(query("RPT_F10_FINANCE_MAINFINADATA", periods+4), &main); err != nil {
+		return nil, err
+	}
+	if len(income) == 0 && len(main) > 0 {
+		return nil, fmt.Errorf("%w: [%s] reports without the non-financial template", ErrUnsupportedCompany, code)
+	}
+	f := &Fundamentals{
+		Code:     code,
+		Income:   make([]*IncomeStatement, 0, len(income)),
+		Balance:  make([]*BalanceSheet, 0, len(balance)),
+		CashFlow: make([]*CashFlowStatement, 0, len(cashFlow)),
+		Ratios:   make([]*KeyRatios, 0, len(main)),
+	}
+	for _, r := range income {
+		f.Income = append(f.Income, r.ToIncomeStatement())
+	}
+	for _, r := range balance {
+		f.Balance = append(f.Balance, r.ToBalanceSheet())
+	}
+	for _, r := range cashFlow {
+		f.CashFlow = append(f.CashFlow, r.ToCashFlowStatement())
+	}
+	for _, r := range main {
+		f.Ratios = append(f.Ratios, r.ToKeyRatios())
+	}
+	FillEPSGrowth(f.Ratios)
+	if len(f.Ratios) > periods {
+		f.Ratios = f.Ratios[:periods]
+	}
+	return f, nil
+}
@@ -0,0 +1,59 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_Fundamentals(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.Fundamentals("0.300059", 4)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestSecuCode(t *testing.T) {
+	for secid, want := range map[string]string{
+		"1.600350": "600350.SH",
+		"0.300059": "300059.SZ",
+		"0.830799": "830799.BJ",
+	} {
+		code, err := spiders.SecuCode(secid)
+		if assert.NoError(t, err) {
+			assert.Equal(t, want, code)
+		}
+	}
+	_, err := spiders.SecuCode("116.00700")
+	assert.Error(t, err)
+}
+
+func TestEastMoneyMainFinance_ToKeyRatios(t *testing.T) {
+	var rows []*spiders.EastMoneyMainFinance
+	data := `[{"REPORT_DATE":"2020-09-30 00:00:00","REPORT_TYPE":"三季报","EPSJB":0.52,"BPS":5.21,"ROEJQ":11.2,"XSMLL":null,"ZCFZL":"-","TOTALOPERATEREVETZ":83.5},` +
+		`{"REPORT_DATE":"2020-06-30 00:00:00","REPORT_TYPE":"中报","EPSJB":0.3},` +
+		`{"REPORT_DATE":"2019-09-30 00:00:00","REPORT_TYPE":"三季报","EPSJB":0.4}]`
+	if !assert.NoError(t, json.Unmarshal([]byte(data), &rows)) {
+		return
+	}
+	ratios := make([]*spiders.KeyRatios, len(rows))
+	for i, r := range rows {
+		ratios[i] = r.ToKeyRatios()
+	}
+	spiders.FillEPSGrowth(ratios)
+	assert.Equal(t, "2020-09-30", ratios[0].ReportDate.Format("2006-01-02"))
+	assert.Equal(t, "三季报", ratios[0].ReportType)
+	assert.InDelta(t, 11.2, *ratios[0].ROE, 1e-9)
+	assert.Nil(t, ratios[0].GrossMargin)
+	assert.Nil(t, ratios[0].DebtRatio)
+	assert.Nil(t, ratios[0].NetMargin)
+	assert.InDelta(t, 30, *ratios[0].EPSGrowth, 1e-9)
+	assert.Nil(t, ratios[1].EPSGrowth) // no 2019 interim report
+	out, err := json.Marshal(ratios[0])
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(out), `"gross_margin":null`)
+	}
+}
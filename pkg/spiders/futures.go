@@ -0,0 +1,213 @@
+package spiders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownProduct is returned for futures products without listed contracts.
+var ErrUnknownProduct = errors.New("unknown futures product")
+
+// FuturesContract is the quote of a futures contract. The main contract (主力合约) of a
+// product is the one with the largest open interest.
+type FuturesContract struct {
+	*MultiStock
+	Product       string `json:"product"` // such as rb or SR
+	DeliveryMonth Month  `json:"delivery_month"`
+	Main          bool   `json:"main"`
+}
+
+// Month is a delivery month, marshalled as 2006-01 and null when unknown.
+type Month struct {
+	time.Time
+}
+
+func (m Month) MarshalJSON() ([]byte, error) {
+	if m.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(m.Format("2006-01"))
+}
+
+func (m *Month) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*m = Month{}
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01", *s, futuresMarket.Location)
+	if err != nil {
+		return err
+	}
+	*m = Month{Time: t}
+	return nil
+}
+
+// FuturesProvider lists the contracts of futures products. The product is the letters of
+// the contract codes, such as rb for 螺纹钢, optionally prefixed by the market number as in
+// 113.rb to scan a single exchange. The continuous contracts East Money quotes, such as
+// 113.rbm (螺纹钢主连), are not listed, they go to KLine and Trend as they are.
+type FuturesProvider interface {
+	FuturesContracts(product string) ([]*FuturesContract, error)
+	MainContract(product string) (*FuturesContract, error)
+}
+
+var _ FuturesProvider = new(EastMoneyProvider)
+
+// FuturesProduct returns the letters before the delivery month of a contract code,
+// empty for the continuous contracts without a month.
+func FuturesProduct(code string) string {
+	i := strings.IndexAny(code, "0123456789")
+	if i <= 0 {
+		return ""
+	}
+	return code[:i]
+}
+
+// DeliveryMonth reads the month of a contract code, rb2105 for 2021-05. 郑商所 codes such
+// as SR105 have a single year digit, read as the first such year not long past at now.
+func DeliveryMonth(code string, now time.Time) time.Time {
+	i := strings.IndexAny(code, "0123456789")
+	if i < 0 {
+		return time.Time{}
+	}
+	digits := code[i:]
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return time.Time{}
+	}
+	var year, month int
+	switch len(digits) {
+	case 4:
+		year, month = 2000+n/100, n%100
+	case 3:
+		year, month = now.Year()/10*10+n/100, n%100
+		if year < now.Year()-1 {
+			year += 10
+		}
+	default:
+		return time.Time{}
+	}
+	if month < 1 || month > 12 {
+		return time.Time{}
+	}
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, futuresMarket.Location)
+}
+
+// futuresScope returns the exchanges a product is listed on, ScopeFutures until a scan of
+// all exchanges has been seen.
+func (p *EastMoneyProvider) futuresScope(product string) Scope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if scope, ok := p.futuresScopes[strings.ToLower(product)]; ok {
+		return scope
+	}
+	return ScopeFutures
+}
+
+// learnFuturesScopes keeps the market numbers of the products of a scan of all exchanges.
+func (p *EastMoneyProvider) learnFuturesScopes(markets map[string]map[int]bool) {
+	scopes := make(map[string]Scope, len(markets))
+	for product, nums := range markets {
+		list := make([]int, 0, len(nums))
+		for num := range nums {
+			list = append(list, num)
+		}
+		sort.Ints(list)
+		filters := make([]string, len(list))
+		for i, num := range list {
+			filters[i] = "m:" + strconv.Itoa(num)
+		}
+		scopes[product] = Scope(strings.Join(filters, ","))
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.futuresScopes = scopes
+}
+
+// FuturesContracts returns the contracts of product in delivery order, the main one marked.
+// The first product without a market number scans all exchanges, the next ones only
+// their own.
+func (p *EastMoneyProvider) FuturesContracts(product string) ([]*FuturesContract, error) {
+	var scope Scope
+	if i := strings.Index(product, "."); i >= 0 {
+		scope = Scope("m:" + product[:i])
+		product = product[i+1:]
+	} else {
+		scope = p.futuresScope(product)
+	}
+	var markets map[string]map[int]bool
+	if scope == ScopeFutures {
+		markets = make(map[string]map[int]bool)
+	}
+	now := time.Now()
+	contracts := make([]*FuturesContract, 0)
+	err := p.scan(scope, multiStockFields, func(item *EastMoneyMultiStockItem) {
+		code := FuturesProduct(item.F12)
+		if markets != nil && code != "" {
+			key := strings.ToLower(code)
+			if markets[key] == nil {
+				markets[key] = make(map[int]bool)
+			}
+			markets[key][item.F13] = true
+		}
+		if !strings.EqualFold(code, product) {
+			return
+		}
+		contracts = append(contracts, &FuturesContract{
+			MultiStock:    item.ToMultiStock(),
+			Product:       code,
+			DeliveryMonth: Month{Time: DeliveryMonth(item.F12, now)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if markets != nil {
+		p.learnFuturesScopes(markets)
+	}
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("%w [%s]", ErrUnknownProduct, product)
+	}
+	MarkMainContract(contracts)
+	return contracts, nil
+}
+
+// MarkMainContract sorts the contracts by delivery month and marks the one with the
+// largest open interest, the nearest among equal ones.
+func MarkMainContract(contracts []*FuturesContract) {
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return contracts[i].DeliveryMonth.Before(contracts[j].DeliveryMonth.Time)
+	})
+	main := 0
+	for i, c := range contracts {
+		c.Main = false
+		if c.OpenInterest > contracts[main].OpenInterest {
+			main = i
+		}
+	}
+	if len(contracts) > 0 {
+		contracts[main].Main = true
+	}
+}
+
+func (p *EastMoneyProvider) MainContract(product string) (*FuturesContract, error) {
+	contracts, err := p.FuturesContracts(product)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contracts {
+		if c.Main {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w [%s]", ErrUnknownProduct, product)
+}
@@ -0,0 +1,78 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_MainContract(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.MainContract("113.rb")
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyProvider_FuturesKLine(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	end := time.Now()
+	data, err := spider.KLine("113.rbm", spiders.FifteenMinutes, end.AddDate(0, 0, -3), end)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestFuturesProduct(t *testing.T) {
+	assert.Equal(t, "rb", spiders.FuturesProduct("rb2105"))
+	assert.Equal(t, "SR", spiders.FuturesProduct("SR105"))
+	assert.Equal(t, "", spiders.FuturesProduct("rbm"))
+
+	now := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2021-05", spiders.DeliveryMonth("rb2105", now).Format("2006-01"))
+	assert.Equal(t, "2021-01", spiders.DeliveryMonth("SR101", now).Format("2006-01"))
+	assert.Equal(t, "2029-09", spiders.DeliveryMonth("SR909", now).Format("2006-01"))
+	assert.True(t, spiders.DeliveryMonth("rbm", now).IsZero())
+}
+
+func TestMarkMainContract(t *testing.T) {
+	now := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	contract := func(code string, oi float64) *spiders.FuturesContract {
+		return &spiders.FuturesContract{
+			MultiStock:    &spiders.MultiStock{Stock: spiders.Stock{Code: code}, OpenInterest: oi},
+			DeliveryMonth: spiders.Month{Time: spiders.DeliveryMonth(code, now)},
+		}
+	}
+	contracts := []*spiders.FuturesContract{contract("rb2105", 1500000), contract("rb2101", 900000), contract("rb2110", 1500000)}
+	spiders.MarkMainContract(contracts)
+	assert.Equal(t, "rb2101", contracts[0].Code)
+	assert.False(t, contracts[0].Main)
+	assert.True(t, contracts[1].Main)
+	assert.False(t, contracts[2].Main)
+
+	data, err := json.Marshal(contracts[1])
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"delivery_month":"2021-05"`)
+	}
+	data, err = json.Marshal(spiders.FuturesContract{MultiStock: &spiders.MultiStock{}})
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"delivery_month":null`)
+	}
+}
+
+func TestEastMoneyMultiStockItem_Futures(t *testing.T) {
+	item := new(spiders.EastMoneyMultiStockItem)
+	data := `{"f1":0,"f2":3712,"f3":45,"f12":"rb2105","f13":113,"f14":"螺纹2105","f18":3700,"f28":3695,"f108":1523401,"f152":2,"f350":3990,"f351":3400}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), item)) {
+		ms := item.ToMultiStock()
+		assert.Equal(t, spiders.MarketFutures, ms.Market)
+		assert.Equal(t, float64(3712), *ms.Price)
+		assert.Equal(t, float64(3695), ms.PreSettlement)
+		assert.Equal(t, float64(1523401), ms.OpenInterest)
+		assert.Equal(t, float64(3990), ms.LimitUp)
+	}
+}
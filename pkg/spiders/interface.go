@@ -1,21 +1,28 @@
 package spiders
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 type KLine struct {
-	Open  float64   `json:"open"`
-	Close float64   `json:"close"`
-	High  float64   `json:"high"`
-	Low   float64   `json:"low"`
-	Time  time.Time `json:"time"`
-	Type  Type      `json:"type"`
+	Open         float64   `json:"open"`
+	Close        float64   `json:"close"`
+	High         float64   `json:"high"`
+	Low          float64   `json:"low"`
+	Time         time.Time `json:"time"`
+	Type         Type      `json:"type"`
+	OpenInterest float64   `json:"open_interest,omitempty"` // 持仓量 of futures
 }
 
 type Trend struct {
-	Time    time.Time `json:"time" time_format:"15:04"`
-	Price   float64   `json:"price"`
-	Volume  int64     `json:"volume"`
-	Incrace float64   `json:"incrace"`
+	Time    time.Time   `json:"time" time_format:"15:04"`
+	Price   float64     `json:"price"`
+	Volume  int64       `json:"volume"`
+	Incrace float64     `json:"incrace"`
+	Session SessionType `json:"session"`
+	// TradingDay tells the nights of futures, which open the next trading day, apart.
+	TradingDay time.Time `json:"trading_day" time_format:"2006-01-02"`
 }
 
 type TrendData struct {
@@ -24,49 +31,85 @@ type TrendData struct {
 }
 
 type Stock struct {
-	Name         string `json:"name"`
-	Code         string `json:"code"`
-	InternalCode string `json:"internal_code"`
-	Type         string `json:"type"`
+	Name         string       `json:"name"`
+	Code         string       `json:"code"`
+	InternalCode string       `json:"internal_code"`
+	Type         string       `json:"type"` // type name of the provider, such as 沪A
+	SecurityType SecurityType `json:"security_type,omitempty"`
+	PinYin       string       `json:"pinyin,omitempty"` // initials of the name, such as DFCF
+	Market       MarketType   `json:"market"`
+	Currency     string       `json:"currency"`
 }
 
-// f2: now price  f3: gains f5 成交量 f6: 成交额 f9 市盈 f12: internal_code f14 name f15 最高 f16 最低 f17今开 f18 昨收 f20 总市值 f21 流通市值 f23市净值
+// f2: now price  f3: gains f5 成交量 f6: 成交额 f8 换手率 f9 市盈 f12: internal_code f14 name f15 最高 f16 最低 f17今开 f18 昨收 f20 总市值 f21 流通市值 f23市净值 f174 52周最高 f175 52周最低
+// The trading fields of MultiStock and StockWithDetail are nil, null in JSON, when East
+// Money sends "-", as for suspended stocks.
 type MultiStock struct {
 	Stock
-	Price          float64 `json:"price"`
-	Gains          float64 `json:"gains"`
-	TrendVolume    float64 `json:"trend_volume"`    // 交易量
-	TurnoverAmount float64 `json:"turnover_amount"` // 成交额
-	High           float64 `json:"high"`            // 最高
-	Low            float64 `json:"low"`             // 最低
-	Open           float64 `json:"open"`            // 今开
-	Close          float64 `json:"close"`           // 昨收
-	TotalValue     float64 `json:"total_value"`     // 总市值
-	Circulation    float64 `json:"circulation"`     // 流通值
-	PBRatio        float64 `json:"pb_ratio"`        // 市净率
+	Price          *float64 `json:"price"`
+	Gains          *float64 `json:"gains"`
+	TrendVolume    *float64 `json:"trend_volume"`    // 交易量
+	TurnoverAmount *float64 `json:"turnover_amount"` // 成交额
+	High           *float64 `json:"high"`            // 最高
+	Low            *float64 `json:"low"`             // 最低
+	Open           *float64 `json:"open"`            // 今开
+	Close          float64  `json:"close"`           // 昨收
+	TotalValue     float64  `json:"total_value"`     // 总市值
+	Circulation    float64  `json:"circulation"`     // 流通值
+	PBRatio        *float64 `json:"pb_ratio"`        // 市净率
+	PERatio        *float64 `json:"pe_ratio"`        // 市盈率(动)
+	PERatioTTM     *float64 `json:"pe_ratio_ttm"`    // 市盈率(TTM)
+	EPS            float64  `json:"eps"`             // 每股收益
+	TurnoverRate   *float64 `json:"turnover_rate"`   // 换手率(%)
+	TotalShares    float64  `json:"total_shares"`    // 总股本
+	FloatShares    float64  `json:"float_shares"`    // 流通股本
+	LimitUp        float64  `json:"limit_up"`        // 涨停
+	LimitDown      float64  `json:"limit_down"`      // 跌停
+	OpenInterest   float64  `json:"open_interest"`   // 持仓量, futures only
+	PreSettlement  float64  `json:"pre_settlement"`  // 昨结, futures only
+	High52Week     *float64 `json:"high_52_week"`    // 52周最高
+	Low52Week      *float64 `json:"low_52_week"`     // 52周最低
 }
 
 type StockWithDetail struct {
 	Stock
-	Gains          float64 `json:"gains"`           // 涨幅
-	High           float64 `json:"high"`            // 最高
-	Low            float64 `json:"low"`             // 最低
-	Open           float64 `json:"open"`            // 今开
-	Close          float64 `json:"close"`           // 昨收
-	TrendVolume    float64 `json:"trend_volume"`    // 交易量
-	TurnoverAmount float64 `json:"turnover_amount"` // 成交额
-	QuantityRatio  float64 `json:"quantity_ratio"`  // 量比
-	LimitUp        float64 `json:"limit_up"`        // 涨停
-	LimitDown      float64 `json:"limit_down"`      // 跌停
-	Circulation    float64 `json:"circulation"`     // 流通值
-	TotalValue     float64 `json:"total_value"`     // 总市值
-	PBRatio        float64 `json:"pb_ratio"`        // 市净率
-	Turnover       int64   `json:"turnover"`        // 交易额
+	Price          *float64 `json:"price"`           // 最新价
+	Gains          *float64 `json:"gains"`           // 涨幅(%)
+	High           *float64 `json:"high"`            // 最高
+	Low            *float64 `json:"low"`             // 最低
+	Open           *float64 `json:"open"`            // 今开
+	Close          float64  `json:"close"`           // 昨收
+	TrendVolume    *float64 `json:"trend_volume"`    // 交易量
+	TurnoverAmount *float64 `json:"turnover_amount"` // 成交额
+	QuantityRatio  *float64 `json:"quantity_ratio"`  // 量比
+	LimitUp        float64  `json:"limit_up"`        // 涨停
+	LimitDown      float64  `json:"limit_down"`      // 跌停
+	Circulation    float64  `json:"circulation"`     // 流通值
+	TotalValue     float64  `json:"total_value"`     // 总市值
+	PBRatio        *float64 `json:"pb_ratio"`        // 市净率
+	PERatio        *float64 `json:"pe_ratio"`        // 市盈率(动)
+	PERatioTTM     *float64 `json:"pe_ratio_ttm"`    // 市盈率(TTM)
+	EPS            float64  `json:"eps"`             // 每股收益
+	TurnoverRate   *float64 `json:"turnover_rate"`   // 换手率(%)
+	Turnover       int64    `json:"turnover"`        // deprecated, the raw f168: turnover_rate scaled by 10^f152
+	TotalShares    float64  `json:"total_shares"`    // 总股本
+	FloatShares    float64  `json:"float_shares"`    // 流通股本
+	High52Week     *float64 `json:"high_52_week"`    // 52周最高
+	Low52Week      *float64 `json:"low_52_week"`     // 52周最低
+}
+
+// Value reads a nullable quote field, 0 if it is nil.
+func Value(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
 }
 
 type Type string
 
 const (
+	OneMinute      Type = "1min"
 	FiveMinutes    Type = "5min"
 	FifteenMinutes Type = "15min"
 	ThirtyMinutes  Type = "30min"
@@ -74,12 +117,41 @@ const (
 	OneDay         Type = "1d"
 	OneWeek        Type = "1w"
 	OneMonth       Type = "1m"
+	OneQuarter     Type = "1q"
+	OneYear        Type = "1y"
 )
 
+// SupportedTypes lists the K-line periods in ascending order.
+var SupportedTypes = []Type{
+	OneMinute, FiveMinutes, FifteenMinutes, ThirtyMinutes, OneHour,
+	OneDay, OneWeek, OneMonth, OneQuarter, OneYear,
+}
+
+var ErrUnsupportedType = errors.New("unsupported kline type")
+
+func (t Type) Valid() bool {
+	for _, st := range SupportedTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Intraday reports whether candles of this period carry a time of day.
+func (t Type) Intraday() bool {
+	switch t {
+	case OneMinute, FiveMinutes, FifteenMinutes, ThirtyMinutes, OneHour:
+		return true
+	default:
+		return false
+	}
+}
+
 type IStock interface {
 	KLine(stockCode string, t Type, start, end time.Time) ([]*KLine, error)
 	Trend(stockCode string, day int, showBefore bool) ([]*Trend, error)
-	Search(key string) ([]*Stock, error)
+	Search(key string, opts SearchOptions) ([]*Stock, error)
 	Stock(code string) (*StockWithDetail, error)
 	MultiStock(codes []string) ([]*MultiStock, error)
 }
@@ -0,0 +1,105 @@
+package spiders
+
+import (
+	"fmt"
+	"time"
+)
+
+// Margin is the margin trading (融资融券) of a stock, or of the whole market, at the
+// close of a trading day. Amounts are in yuan, volumes in shares, nil when the report
+// does not carry them. Date is the date of the day in China time, as the daily KLine.
+type Margin struct {
+	Date             time.Time `json:"date"`
+	FinancingBalance *float64  `json:"financing_balance"` // 融资余额
+	FinancingBuy     *float64  `json:"financing_buy"`     // 融资买入额
+	FinancingRepay   *float64  `json:"financing_repay"`   // 融资偿还额
+	FinancingNetBuy  *float64  `json:"financing_net_buy"` // 融资净买入
+	LendingBalance   *float64  `json:"lending_balance"`   // 融券余额
+	LendingVolume    *float64  `json:"lending_volume"`    // 融券余量, null market-wide
+	LendingSell      *float64  `json:"lending_sell"`      // 融券卖出量
+	LendingRepay     *float64  `json:"lending_repay"`     // 融券偿还量
+	Balance          *float64  `json:"balance"`           // 融资融券余额
+}
+
+// MarginProvider fetches the daily margin balances, oldest first.
+type MarginProvider interface {
+	Margin(code string, start, end time.Time) ([]*Margin, error)
+	MarketMargin(start, end time.Time) ([]*Margin, error)
+}
+
+var _ MarginProvider = new(EastMoneyProvider)
+
+// EastMoneyMargin is a row of RPTA_WEB_RZRQ_GGMX for a stock, or RPTA_RZRQ_LSHJ for the
+// market, whose date column is DIM_DATE.
+type EastMoneyMargin struct {
+	Date    string          `json:"DATE"`
+	DimDate string          `json:"DIM_DATE"`
+	RZYE    EastMoneyNumber `json:"RZYE"`
+	RZMRE   EastMoneyNumber `json:"RZMRE"`
+	RZCHE   EastMoneyNumber `json:"RZCHE"`
+	RZJME   EastMoneyNumber `json:"RZJME"`
+	RQYE    EastMoneyNumber `json:"RQYE"`
+	RQYL    EastMoneyNumber `json:"RQYL"`
+	RQMCL   EastMoneyNumber `json:"RQMCL"`
+	RQCHL   EastMoneyNumber `json:"RQCHL"`
+	RZRQYE  EastMoneyNumber `json:"RZRQYE"`
+}
+
+func (r *EastMoneyMargin) ToMargin() *Margin {
+	date := r.Date
+	if date == "" {
+		date = r.DimDate
+	}
+	return &Margin{
+		Date:             parseDataCenterTime(date),
+		FinancingBalance: r.RZYE.Nullable(0),
+		FinancingBuy:     r.RZMRE.Nullable(0),
+		FinancingRepay:   r.RZCHE.Nullable(0),
+		FinancingNetBuy:  r.RZJME.Nullable(0),
+		LendingBalance:   r.RQYE.Nullable(0),
+		LendingVolume:    r.RQYL.Nullable(0),
+		LendingSell:      r.RQMCL.Nullable(0),
+		LendingRepay:     r.RQCHL.Nullable(0),
+		Balance:          r.RZRQYE.Nullable(0),
+	}
+}
+
+// margins pages through a margin report between start and end.
+func (p *EastMoneyProvider) margins(report, dateColumn, filter string, start, end time.Time) ([]*Margin, error) {
+	filter += fmt.Sprintf("(%s>='%s')(%s<='%s')",
+		dateColumn, start.In(cnMarket.Location).Format(dataCenterDateFormat),
+		dateColumn, end.In(cnMarket.Location).Format(dataCenterDateFormat))
+	margins := make([]*Margin, 0)
+	for page := 1; ; page++ {
+		var rows []*EastMoneyMargin
+		pages, err := p.dataCenter(DataCenterQuery{
+			Report:   report,
+			Filter:   filter,
+			Sort:     dateColumn,
+			Page:     page,
+			PageSize: 500,
+		}, &rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			margins = append(margins, r.ToMargin())
+		}
+		if page >= pages {
+			return margins, nil
+		}
+	}
+}
+
+// Margin returns the days of an A-share, stocks outside the margin list have none.
+func (p *EastMoneyProvider) Margin(code string, start, end time.Time) ([]*Margin, error) {
+	if _, err := SecuCode(code); err != nil {
+		return nil, err
+	}
+	return p.margins("RPTA_WEB_RZRQ_GGMX", "DATE", fmt.Sprintf(`(SCODE="%s")`, plainCode(code)), start, end)
+}
+
+// MarketMargin returns the totals of the Shanghai and Shenzhen exchanges.
+func (p *EastMoneyProvider) MarketMargin(start, end time.Time) ([]*Margin, error) {
+	return p.margins("RPTA_RZRQ_LSHJ", "DIM_DATE", "", start, end)
+}
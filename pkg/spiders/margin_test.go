@@ -0,0 +1,45 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_Margin(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	end := time.Now()
+	data, err := spider.Margin("0.300059", end.AddDate(0, -1, 0), end)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyMargin_ToMargin(t *testing.T) {
+	r := new(spiders.EastMoneyMargin)
+	data := `{"DATE":"2020-11-02 00:00:00","SCODE":"300059","RZYE":12345678901.0,"RZMRE":987654321,"RZCHE":876543210,` +
+		`"RZJME":111111111,"RQYE":23456789,"RQYL":1234567,"RQMCL":12345,"RQCHL":23456,"RZRQYE":12369135690.0}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), r)) {
+		m := r.ToMargin()
+		market := spiders.MarketOf("0.300059")
+		// the same instant as the daily candle of the day
+		kline, _ := time.ParseInLocation("2006-01-02", "2020-11-02", market.Location)
+		assert.Equal(t, kline, m.Date)
+		assert.Equal(t, 12345678901.0, *m.FinancingBalance)
+		assert.Equal(t, float64(1234567), *m.LendingVolume)
+		assert.Equal(t, 12369135690.0, *m.Balance)
+	}
+	market := new(spiders.EastMoneyMargin)
+	if assert.NoError(t, json.Unmarshal([]byte(`{"DIM_DATE":"2020-11-02 00:00:00","RZYE":1.5e12,"RQYL":null}`), market)) {
+		m := market.ToMargin()
+		assert.Equal(t, "2020-11-02", m.Date.Format("2006-01-02"))
+		assert.Equal(t, 1.5e12, *m.FinancingBalance)
+		assert.Nil(t, m.LendingVolume)
+		assert.Nil(t, m.LendingSell, "not sent at all")
+	}
+	_, err := (&spiders.EastMoneyProvider{}).Margin("116.00700", time.Now(), time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package spiders
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MarketType string
+
+const (
+	MarketCN MarketType = "cn"
+	MarketHK MarketType = "hk"
+	MarketUS MarketType = "us"
+	// MarketFutures is the commodity and financial futures of the mainland exchanges.
+	MarketFutures MarketType = "futures"
+)
+
+type SessionType string
+
+const (
+	SessionPre     SessionType = "pre"
+	SessionRegular SessionType = "regular"
+	SessionPost    SessionType = "post"
+	SessionNight   SessionType = "night"
+)
+
+// Session is a trading period in minutes since midnight of the market's own time zone.
+// A night session past midnight closes after 24*60.
+type Session struct {
+	Open  int
+	Close int
+}
+
+func (s Session) contains(minute int) bool {
+	return (minute >= s.Open && minute <= s.Close) || (minute+24*60 >= s.Open && minute+24*60 <= s.Close)
+}
+
+type Market struct {
+	Type     MarketType
+	Currency string
+	Location *time.Location
+	Sessions []Session // regular sessions in order
+	// Night is the evening session opening the next trading day, nil for markets
+	// without one. It is the widest of the products, most of them close earlier.
+	Night      *Session
+	PriceLimit bool // whether the exchange enforces 涨停/跌停
+}
+
+func loadLocation(name string, offset int) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(name, offset)
+	}
+	return loc
+}
+
+var (
+	cnMarket = &Market{
+		Type:       MarketCN,
+		Currency:   "CNY",
+		Location:   loadLocation("Asia/Shanghai", 8*3600),
+		Sessions:   []Session{{Open: 9*60 + 30, Close: 11*60 + 30}, {Open: 13 * 60, Close: 15 * 60}},
+		PriceLimit: true,
+	}
+	hkMarket = &Market{
+		Type:     MarketHK,
+		Currency: "HKD",
+		Location: loadLocation("Asia/Hong_Kong", 8*3600),
+		Sessions: []Session{{Open: 9*60 + 30, Close: 12 * 60}, {Open: 13 * 60, Close: 16 * 60}},
+	}
+	usMarket = &Market{
+		Type:     MarketUS,
+		Currency: "USD",
+		Location: loadLocation("America/New_York", -5*3600),
+		Sessions: []Session{{Open: 9*60 + 30, Close: 16 * 60}},
+	}
+	futuresMarket = &Market{
+		Type:       MarketFutures,
+		Currency:   "CNY",
+		Location:   cnMarket.Location,
+		Sessions:   []Session{{Open: 9 * 60, Close: 10*60 + 15}, {Open: 10*60 + 30, Close: 11*60 + 30}, {Open: 13*60 + 30, Close: 15 * 60}},
+		Night:      &Session{Open: 21 * 60, Close: 26*60 + 30},
+		PriceLimit: true,
+	}
+	// cffexMarket is the financial futures of 中金所, trading with the stocks and no night.
+	cffexMarket = &Market{
+		Type:       MarketFutures,
+		Currency:   "CNY",
+		Location:   cnMarket.Location,
+		Sessions:   []Session{{Open: 9*60 + 30, Close: 11*60 + 30}, {Open: 13 * 60, Close: 15 * 60}},
+		PriceLimit: true,
+	}
+)
+
+// MarketOf resolves the market from the number before the dot of a secid such as
+// "116.00700" or "105.AAPL". Unknown numbers fall back to the A-share market.
+func MarketOf(secid string) *Market {
+	i := strings.Index(secid, ".")
+	if i < 0 {
+		return cnMarket
+	}
+	num, err := strconv.Atoi(secid[:i])
+	if err != nil {
+		return cnMarket
+	}
+	return marketOfNum(num)
+}
+
+func marketOfNum(num int) *Market {
+	switch num {
+	case 116, 128:
+		return hkMarket
+	case 105, 106, 107, 153:
+		return usMarket
+	case 113, 114, 115, 142, 225: // 上期所, 大商所, 郑商所, 上期能源, 广期所
+		return futuresMarket
+	case 8, 220: // 中金所
+		return cffexMarket
+	default:
+		return cnMarket
+	}
+}
+
+// SessionOf classifies a time into pre-market, regular or post-market trading.
+func (m *Market) SessionOf(t time.Time) SessionType {
+	t = t.In(m.Location)
+	minute := t.Hour()*60 + t.Minute()
+	if m.Night != nil && m.Night.contains(minute) {
+		return SessionNight
+	}
+	for _, s := range m.Sessions {
+		if s.contains(minute) {
+			return SessionRegular
+		}
+	}
+	if minute < m.Sessions[0].Open {
+		return SessionPre
+	}
+	if minute > m.Sessions[len(m.Sessions)-1].Close {
+		return SessionPost
+	}
+	// lunch break prints, e.g. the 11:30 close repeated by some feeds
+	return SessionRegular
+}
+
+// InSession reports whether t is inside a regular session or the night session. Unlike
+// SessionOf, the breaks between sessions, such as the A-share lunch, are not trading.
+func (m *Market) InSession(t time.Time) bool {
+	t = t.In(m.Location)
+	minute := t.Hour()*60 + t.Minute()
+	if m.Night != nil && m.Night.contains(minute) {
+		return true
+	}
+	for _, s := range m.Sessions {
+		if s.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// TradingDay returns the date of the trading day t belongs to. The night session opens
+// the next trading day, from Friday night it is Monday. Holidays are not known.
+func (m *Market) TradingDay(t time.Time) time.Time {
+	t = t.In(m.Location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, m.Location)
+	if m.Night == nil {
+		return day
+	}
+	minute := t.Hour()*60 + t.Minute()
+	switch {
+	case minute >= m.Night.Open:
+		day = day.AddDate(0, 0, 1)
+	case minute+24*60 > m.Night.Close:
+		return day
+	}
+	// past midnight the night belongs to the day already, unless it is the weekend
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, 2)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// Settled returns the time before which the candles of the market are final at now: the
+// start of the trading day in progress, its night included, or now once the day closed.
+func (m *Market) Settled(now time.Time) time.Time {
+	day := m.TradingDay(now)
+	if m.Night == nil && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+		return now
+	}
+	closing := day.Add(time.Duration(m.Sessions[len(m.Sessions)-1].Close) * time.Minute)
+	if !now.Before(closing) {
+		return now
+	}
+	if m.Night == nil {
+		return day
+	}
+	// the night opening the day is the evening of the weekday before
+	eve := day.AddDate(0, 0, -1)
+	for eve.Weekday() == time.Saturday || eve.Weekday() == time.Sunday {
+		eve = eve.AddDate(0, 0, -1)
+	}
+	return eve.Add(time.Duration(m.Night.Open) * time.Minute)
+}
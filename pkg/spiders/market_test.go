@@ -0,0 +1,91 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketOf(t *testing.T) {
+	assert.Equal(t, spiders.MarketCN, spiders.MarketOf("1.600350").Type)
+	assert.Equal(t, spiders.MarketCN, spiders.MarketOf("90.BK0729").Type)
+	assert.Equal(t, spiders.MarketHK, spiders.MarketOf("116.00700").Type)
+	assert.Equal(t, spiders.MarketUS, spiders.MarketOf("105.AAPL").Type)
+	assert.Equal(t, "USD", spiders.MarketOf("106.BABA").Currency)
+	assert.Equal(t, spiders.MarketCN, spiders.MarketOf("600350").Type)
+}
+
+func TestMarket_SessionOf(t *testing.T) {
+	us := spiders.MarketOf("105.AAPL")
+	at := func(hour, min int) time.Time {
+		return time.Date(2020, 11, 2, hour, min, 0, 0, us.Location)
+	}
+	assert.Equal(t, spiders.SessionPre, us.SessionOf(at(8, 0)))
+	assert.Equal(t, spiders.SessionRegular, us.SessionOf(at(12, 0)))
+	assert.Equal(t, spiders.SessionPost, us.SessionOf(at(17, 30)))
+
+	hk := spiders.MarketOf("116.00700")
+	assert.Equal(t, spiders.SessionRegular, hk.SessionOf(time.Date(2020, 11, 2, 15, 30, 0, 0, hk.Location)))
+	cn := spiders.MarketOf("0.300059")
+	assert.Equal(t, spiders.SessionPost, cn.SessionOf(time.Date(2020, 11, 2, 15, 30, 0, 0, cn.Location)))
+}
+
+func TestMarket_InSession(t *testing.T) {
+	cn := spiders.MarketOf("1.600350")
+	at := func(hour, min int) time.Time {
+		return time.Date(2020, 11, 2, hour, min, 0, 0, cn.Location)
+	}
+	assert.True(t, cn.InSession(at(10, 0)))
+	assert.False(t, cn.InSession(at(12, 0)), "lunch break")
+	assert.True(t, cn.InSession(at(13, 0)))
+	assert.False(t, cn.InSession(at(15, 30)))
+
+	shfe := spiders.MarketOf("113.rb2105")
+	assert.False(t, shfe.InSession(at(10, 20)), "morning break")
+	assert.True(t, shfe.InSession(at(10, 30)))
+	assert.True(t, shfe.InSession(at(23, 0)))
+}
+
+func TestMarket_Futures(t *testing.T) {
+	shfe := spiders.MarketOf("113.rb2105")
+	assert.Equal(t, spiders.MarketFutures, shfe.Type)
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 11, day, hour, min, 0, 0, shfe.Location)
+	}
+	assert.Equal(t, spiders.SessionNight, shfe.SessionOf(at(6, 21, 30)))
+	assert.Equal(t, spiders.SessionNight, shfe.SessionOf(at(7, 1, 0)))
+	assert.Equal(t, spiders.SessionRegular, shfe.SessionOf(at(9, 9, 5)))
+	assert.Equal(t, spiders.SessionPost, shfe.SessionOf(at(9, 15, 30)))
+
+	// Friday night and its hours past midnight open Monday 2020-11-09
+	assert.Equal(t, at(9, 0, 0), shfe.TradingDay(at(6, 21, 30)))
+	assert.Equal(t, at(9, 0, 0), shfe.TradingDay(at(7, 1, 0)))
+	assert.Equal(t, at(10, 0, 0), shfe.TradingDay(at(10, 1, 0)))
+	assert.Equal(t, at(10, 0, 0), shfe.TradingDay(at(10, 14, 0)))
+
+	cffex := spiders.MarketOf("8.IF2012")
+	assert.Equal(t, spiders.MarketFutures, cffex.Type)
+	assert.Nil(t, cffex.Night)
+	assert.Equal(t, spiders.SessionPost, cffex.SessionOf(at(9, 21, 30)))
+	cn := spiders.MarketOf("1.600350")
+	assert.Equal(t, at(6, 0, 0), cn.TradingDay(at(6, 21, 30)))
+}
+
+func TestMarket_Settled(t *testing.T) {
+	cn := spiders.MarketOf("1.600350")
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 11, day, hour, min, 0, 0, cn.Location)
+	}
+	// Monday 2020-11-09 in progress, then closed, then the weekend
+	assert.Equal(t, at(9, 0, 0), cn.Settled(at(9, 10, 0)))
+	assert.Equal(t, at(9, 15, 30), cn.Settled(at(9, 15, 30)))
+	assert.Equal(t, at(7, 10, 0), cn.Settled(at(7, 10, 0)))
+
+	// Monday opens with the night of Friday 2020-11-06
+	shfe := spiders.MarketOf("113.rb2105")
+	assert.Equal(t, at(6, 21, 0), shfe.Settled(at(7, 1, 0)))
+	assert.Equal(t, at(6, 21, 0), shfe.Settled(at(9, 10, 0)))
+	assert.Equal(t, at(9, 15, 30), shfe.Settled(at(9, 15, 30)))
+}
@@ -0,0 +1,213 @@
+package spiders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	easyMoneyNewsSearchAPI = "https://search-api-web.eastmoney.com/search/jsonp"
+	easyMoneyNoticeAPI     = "https://np-anotice-stock.eastmoney.com/api/security/ann"
+	easyMoneyNoticeURL     = "https://data.eastmoney.com/notices/detail/%s/%s.html"
+	noticeTimeFormat       = "2006-01-02 15:04:05"
+	newsCallback           = "jQuery"
+)
+
+type NewsKind string
+
+const (
+	NewsArticle      NewsKind = "news"
+	NewsAnnouncement NewsKind = "announcement"
+)
+
+// News is an article mentioning a stock or an announcement it filed with its exchange.
+type News struct {
+	ID      string    `json:"id"`
+	Kind    NewsKind  `json:"kind"`
+	Code    string    `json:"code"`
+	Title   string    `json:"title"`
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	URL     string    `json:"url"`
+	Summary string    `json:"summary"`
+}
+
+// NewsProvider pages through the news and announcements of a stock, latest first.
+type NewsProvider interface {
+	News(code string, page, size int) ([]*News, error)
+	Announcements(code string, page, size int) ([]*News, error)
+}
+
+var _ NewsProvider = new(EastMoneyProvider)
+
+var highlightTag = regexp.MustCompile(`</?em>`)
+
+// plainCode strips the market number of a secid.
+func plainCode(secid string) string {
+	if i := strings.Index(secid, "."); i >= 0 {
+		return secid[i+1:]
+	}
+	return secid
+}
+
+// EastMoneyNewsSearch is the result of the article search, matches are wrapped in <em>.
+type EastMoneyNewsSearch struct {
+	Result struct {
+		CMSArticleWebOld []struct {
+			Code      string `json:"code"`
+			Date      string `json:"date"`
+			Title     string `json:"title"`
+			Content   string `json:"content"`
+			MediaName string `json:"mediaName"`
+			URL       string `json:"url"`
+		} `json:"cmsArticleWebOld"`
+	} `json:"result"`
+}
+
+func (s *EastMoneyNewsSearch) ToNews(secid string) []*News {
+	list := make([]*News, len(s.Result.CMSArticleWebOld))
+	for i, a := range s.Result.CMSArticleWebOld {
+		t, _ := time.ParseInLocation(noticeTimeFormat, a.Date, cnMarket.Location)
+		list[i] = &News{
+			ID:      a.Code,
+			Kind:    NewsArticle,
+			Code:    secid,
+			Title:   highlightTag.ReplaceAllString(a.Title, ""),
+			Time:    t,
+			Source:  a.MediaName,
+			URL:     a.URL,
+			Summary: highlightTag.ReplaceAllString(a.Content, ""),
+		}
+	}
+	return list
+}
+
+// News searches the articles mentioning the code of the secid.
+func (p *EastMoneyProvider) News(code string, page, size int) ([]*News, error) {
+	if p.httpClient == nil {
+		p.httpClient = httpClient
+	}
+	search, err := json.Marshal(map[string]interface{}{
+		"uid":           "",
+		"keyword":       plainCode(code),
+		"type":          []string{"cmsArticleWebOld"},
+		"client":        "web",
+		"clientType":    "web",
+		"clientVersion": "curr",
+		"param": map[string]interface{}{
+			"cmsArticleWebOld": map[string]interface{}{
+				"searchScope": "default",
+				"sort":        "time",
+				"pageIndex":   page,
+				"pageSize":    size,
+				"preTag":      "<em>",
+				"postTag":     "</em>",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	param := url.Values{}
+	param.Set("cb", newsCallback)
+	param.Set("param", string(search))
+	u := fmt.Sprintf("%s?%s", easyMoneyNewsSearchAPI, param.Encode())
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// the response is JSONP: jQuery({...})
+	body = bytes.TrimSpace(body)
+	body = bytes.TrimPrefix(body, []byte(newsCallback+"("))
+	body = bytes.TrimSuffix(bytes.TrimSuffix(body, []byte(";")), []byte(")"))
+	s := new(EastMoneyNewsSearch)
+	if err := json.Unmarshal(body, s); err != nil {
+		return nil, err
+	}
+	return s.ToNews(code), nil
+}
+
+// EastMoneyNotice is a page of the announcement list of the exchanges.
+type EastMoneyNotice struct {
+	Data struct {
+		List []struct {
+			ArtCode     string `json:"art_code"`
+			Title       string `json:"title"`
+			DisplayTime string `json:"display_time"`
+			NoticeDate  string `json:"notice_date"`
+			Columns     []struct {
+				ColumnName string `json:"column_name"`
+			} `json:"columns"`
+		} `json:"list"`
+		TotalHits int `json:"total_hits"`
+	} `json:"data"`
+}
+
+func (s *EastMoneyNotice) ToNews(secid string) []*News {
+	code := plainCode(secid)
+	list := make([]*News, len(s.Data.List))
+	for i, n := range s.Data.List {
+		// display_time carries milliseconds after a colon: 2020-11-02 19:50:14:530
+		display := n.DisplayTime
+		if len(display) > len(noticeTimeFormat) {
+			display = display[:len(noticeTimeFormat)]
+		}
+		t, err := time.ParseInLocation(noticeTimeFormat, display, cnMarket.Location)
+		if err != nil {
+			t, _ = time.ParseInLocation(noticeTimeFormat, n.NoticeDate, cnMarket.Location)
+		}
+		columns := make([]string, len(n.Columns))
+		for j, c := range n.Columns {
+			columns[j] = c.ColumnName
+		}
+		list[i] = &News{
+			ID:      n.ArtCode,
+			Kind:    NewsAnnouncement,
+			Code:    secid,
+			Title:   n.Title,
+			Time:    t,
+			Source:  "exchange",
+			URL:     fmt.Sprintf(easyMoneyNoticeURL, code, n.ArtCode),
+			Summary: strings.Join(columns, ","),
+		}
+	}
+	return list
+}
+
+// Announcements lists the exchange filings of an A-share, the summary is their categories.
+func (p *EastMoneyProvider) Announcements(code string, page, size int) ([]*News, error) {
+	if MarketOf(code).Type != MarketCN {
+		return nil, fmt.Errorf("%w [%s]", ErrUnsupportedMarket, code)
+	}
+	param := url.Values{}
+	param.Set("sr", "-1")
+	param.Set("page_size", strconv.Itoa(size))
+	param.Set("page_index", strconv.Itoa(page))
+	param.Set("ann_type", "A")
+	param.Set("client_source", "web")
+	param.Set("stock_list", plainCode(code))
+	param.Set("f_node", "0")
+	param.Set("s_node", "0")
+	s := new(EastMoneyNotice)
+	if err := p.getJSON(fmt.Sprintf("%s?%s", easyMoneyNoticeAPI, param.Encode()), s); err != nil {
+		return nil, err
+	}
+	return s.ToNews(code), nil
+}
@@ -0,0 +1,55 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_News(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.News("0.300059", 1, 10)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyProvider_Announcements(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.Announcements("0.300059", 1, 10)
+	if assert.NoError(t, err) {
+		out, _ := json.Marshal(data)
+		t.Log(string(out))
+	}
+}
+
+func TestEastMoneyNotice_ToNews(t *testing.T) {
+	s := new(spiders.EastMoneyNotice)
+	data := `{"data":{"list":[{"art_code":"AN202011021428146584","title":"东方财富:关于可转债转股的公告",` +
+		`"display_time":"2020-11-02 19:50:14:530","notice_date":"2020-11-03 00:00:00","columns":[{"column_name":"可转债转股"}]}],"total_hits":1}}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), s)) {
+		list := s.ToNews("0.300059")
+		if assert.Len(t, list, 1) {
+			assert.Equal(t, spiders.NewsAnnouncement, list[0].Kind)
+			assert.Equal(t, "2020-11-02 19:50", list[0].Time.Format("2006-01-02 15:04"))
+			assert.Equal(t, "https://data.eastmoney.com/notices/detail/300059/AN202011021428146584.html", list[0].URL)
+			assert.Equal(t, "可转债转股", list[0].Summary)
+		}
+	}
+}
+
+func TestEastMoneyNewsSearch_ToNews(t *testing.T) {
+	s := new(spiders.EastMoneyNewsSearch)
+	data := `{"result":{"cmsArticleWebOld":[{"code":"202011021681234567","date":"2020-11-02 09:30:00",` +
+		`"title":"<em>东方财富</em>三季报净利增长","content":"<em>东方财富</em>发布...","mediaName":"证券时报","url":"http://finance.eastmoney.com/a/202011021681234567.html"}]}}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), s)) {
+		list := s.ToNews("0.300059")
+		if assert.Len(t, list, 1) {
+			assert.Equal(t, "东方财富三季报净利增长", list[0].Title)
+			assert.Equal(t, "证券时报", list[0].Source)
+			assert.Equal(t, "0.300059", list[0].Code)
+		}
+	}
+}
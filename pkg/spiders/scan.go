@@ -0,0 +1,162 @@
+package spiders
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scope selects a list of securities to scan, as a clist fs filter.
+type Scope string
+
+const (
+	// ScopeCN is every A-share of Shanghai, Shenzhen and Beijing.
+	ScopeCN    Scope = "m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23,m:0+t:81+s:2048"
+	ScopeIndex Scope = "m:1+s:2,m:0+t:5"
+	// ScopeFund is the exchange traded ETFs and LOFs.
+	ScopeFund Scope = "b:MK0021,b:MK0022,b:MK0023,b:MK0024,b:MK0404,b:MK0405,b:MK0406,b:MK0407"
+	// ScopeBond is the listed convertible bonds.
+	ScopeBond Scope = "b:MK0354"
+	ScopeHK   Scope = "m:128+t:3,m:128+t:4,m:128+t:1,m:128+t:2"
+	ScopeUS   Scope = "m:105,m:106,m:107"
+	// ScopeFutures is the contracts of the mainland futures exchanges.
+	ScopeFutures Scope = "m:113,m:114,m:115,m:142,m:225,m:8,m:220"
+)
+
+// ScopeTypes is the security type of the securities of each scope.
+var ScopeTypes = map[Scope]SecurityType{
+	ScopeCN:      SecurityAShare,
+	ScopeIndex:   SecurityIndex,
+	ScopeFund:    SecurityFund,
+	ScopeBond:    SecurityBond,
+	ScopeHK:      SecurityHK,
+	ScopeUS:      SecurityUS,
+	ScopeFutures: SecurityFutures,
+}
+
+// Scanner lists the quotes of a whole scope at once.
+type Scanner interface {
+	Scan(scope Scope) ([]*MultiStock, error)
+}
+
+var _ Scanner = new(EastMoneyProvider)
+
+// Symbol is an entry of the list of listed securities.
+type Symbol struct {
+	Stock
+	ListingDate time.Time `json:"listing_date"`
+}
+
+// SymbolLister lists the securities of a scope without their quotes.
+type SymbolLister interface {
+	Symbols(scope Scope) ([]*Symbol, error)
+}
+
+var _ SymbolLister = new(EastMoneyProvider)
+
+const (
+	scanPageSize = 100
+	symbolFields = "f12,f13,f14,f26"
+)
+
+// scan pages through clist ordered by code, so that the pages do not shift while the
+// prices move, until the reported total is read. Each item is passed once to fn.
+func (p *EastMoneyProvider) scan(scope Scope, fields string, fn func(item *EastMoneyMultiStockItem)) error {
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		param := url.Values{}
+		param.Set("pn", strconv.Itoa(page))
+		param.Set("pz", strconv.Itoa(scanPageSize))
+		param.Set("po", "0")
+		param.Set("fid", "f12")
+		param.Set("fs", string(scope))
+		param.Set("fields", fields)
+		s, err := p.clist(param)
+		if err != nil {
+			return err
+		}
+		for _, item := range s.Data.Diff {
+			key := strconv.Itoa(item.F13) + "." + item.F12
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fn(item)
+		}
+		if len(s.Data.Diff) == 0 || page*scanPageSize >= s.Data.Total {
+			return nil
+		}
+	}
+}
+
+func (p *EastMoneyProvider) Scan(scope Scope) ([]*MultiStock, error) {
+	list := make([]*MultiStock, 0)
+	err := p.scan(scope, multiStockFields, func(item *EastMoneyMultiStockItem) {
+		list = append(list, item.ToMultiStock())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// scopeTypeNames are the type names of the search results of each security type, for
+// the symbols listed without one.
+var scopeTypeNames = map[SecurityType]string{
+	SecurityIndex:   "指数",
+	SecurityFund:    "基金",
+	SecurityBond:    "债券",
+	SecurityHK:      "港股",
+	SecurityUS:      "美股",
+	SecurityFutures: "期货",
+}
+
+// typeName guesses the type name of a search result from the exchange of an A-share and
+// the security type of others, so 1.600350 is 沪A.
+func typeName(num int, code string, t SecurityType) string {
+	if t != SecurityAShare {
+		return scopeTypeNames[t]
+	}
+	switch {
+	case num == 1:
+		return "沪A"
+	case strings.HasPrefix(code, "8"), strings.HasPrefix(code, "4"):
+		return "京A"
+	default:
+		return "深A"
+	}
+}
+
+// ToSymbol converts a row of a symbol scan of a scope, f26 is the listing date as yyyymmdd.
+func (ms *EastMoneyMultiStockItem) ToSymbol(scope Scope) *Symbol {
+	market := marketOfNum(ms.F13)
+	s := &Symbol{
+		Stock: Stock{
+			Name:         ms.F14,
+			Code:         ms.F12,
+			InternalCode: strconv.Itoa(ms.F13) + "." + ms.F12,
+			Type:         typeName(ms.F13, ms.F12, ScopeTypes[scope]),
+			SecurityType: ScopeTypes[scope],
+			Market:       market.Type,
+			Currency:     market.Currency,
+		},
+	}
+	if ms.F26.Valid && ms.F26.Value > 0 {
+		s.ListingDate, _ = time.ParseInLocation(timeFormat, strconv.FormatInt(int64(ms.F26.Value), 10), market.Location)
+	}
+	return s
+}
+
+// Symbols lists the securities of a scope. clist carries no pinyin nor type name, PinYin
+// is left empty and Type guessed from the scope.
+func (p *EastMoneyProvider) Symbols(scope Scope) ([]*Symbol, error) {
+	list := make([]*Symbol, 0)
+	err := p.scan(scope, symbolFields, func(item *EastMoneyMultiStockItem) {
+		list = append(list, item.ToSymbol(scope))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
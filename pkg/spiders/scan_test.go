@@ -0,0 +1,25 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyMultiStockItem_ToSymbol(t *testing.T) {
+	item := new(spiders.EastMoneyMultiStockItem)
+	if assert.NoError(t, json.Unmarshal([]byte(`{"f12":"300059","f13":0,"f14":"东方财富","f26":20100319}`), item)) {
+		s := item.ToSymbol(spiders.ScopeCN)
+		assert.Equal(t, "0.300059", s.InternalCode)
+		assert.Equal(t, spiders.SecurityAShare, s.SecurityType)
+		assert.Equal(t, "深A", s.Type)
+		assert.Equal(t, "2010-03-19", s.ListingDate.Format("2006-01-02"))
+	}
+	if assert.NoError(t, json.Unmarshal([]byte(`{"f12":"00700","f13":116,"f14":"腾讯控股","f26":"-"}`), item)) {
+		s := item.ToSymbol(spiders.ScopeHK)
+		assert.Equal(t, spiders.MarketHK, s.Market)
+		assert.Equal(t, "港股", s.Type)
+		assert.True(t, s.ListingDate.IsZero())
+	}
+}
@@ -0,0 +1,135 @@
+package spiders
+
+import (
+	"sort"
+	"strings"
+)
+
+// SecurityType is the kind of a security, used to filter searches.
+type SecurityType string
+
+const (
+	SecurityAShare  SecurityType = "a_share"
+	SecurityIndex   SecurityType = "index"
+	SecurityFund    SecurityType = "fund"
+	SecurityBond    SecurityType = "bond"
+	SecurityHK      SecurityType = "hk"
+	SecurityUS      SecurityType = "us"
+	SecurityFutures SecurityType = "futures"
+	SecurityOther   SecurityType = "other"
+)
+
+var SecurityTypes = []SecurityType{
+	SecurityAShare, SecurityIndex, SecurityFund, SecurityBond, SecurityHK, SecurityUS, SecurityFutures, SecurityOther,
+}
+
+func (t SecurityType) Valid() bool {
+	for _, st := range SecurityTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchOptions pages and filters a search. Page counts from 1, zero values mean the
+// first page of 20 results of any type.
+type SearchOptions struct {
+	Page     int
+	PageSize int
+	Types    []SecurityType
+}
+
+const (
+	defaultSearchPageSize = 20
+	// maxFilteredSearch is the number of matches filtered locally when Types are given,
+	// pages of a filtered search are cut from them.
+	maxFilteredSearch = 100
+)
+
+func (o SearchOptions) normalize() SearchOptions {
+	if o.Page <= 0 {
+		o.Page = 1
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = defaultSearchPageSize
+	}
+	return o
+}
+
+func (o SearchOptions) accepts(t SecurityType) bool {
+	if len(o.Types) == 0 {
+		return true
+	}
+	for _, st := range o.Types {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifySecurity derives the SecurityType from the market and the type name of a
+// search result such as 沪A, 指数 or 基金.
+func ClassifySecurity(market MarketType, typeName string) SecurityType {
+	switch {
+	case market == MarketHK:
+		return SecurityHK
+	case market == MarketUS:
+		return SecurityUS
+	case market == MarketFutures:
+		return SecurityFutures
+	case strings.Contains(typeName, "指数"):
+		return SecurityIndex
+	case strings.Contains(typeName, "基金"), strings.Contains(typeName, "ETF"), strings.Contains(typeName, "LOF"):
+		return SecurityFund
+	case strings.Contains(typeName, "债"):
+		return SecurityBond
+	case strings.HasSuffix(typeName, "A"), strings.Contains(typeName, "科创板"), strings.Contains(typeName, "创业板"):
+		return SecurityAShare
+	default:
+		return SecurityOther
+	}
+}
+
+// searchRank orders the matches of key: exact code or pinyin first, then prefixes of the
+// code, pinyin or name, then the rest.
+func searchRank(key string, s *Stock) int {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	pinyin := strings.ToUpper(s.PinYin)
+	switch {
+	case s.Code == key || pinyin == key || s.Name == key:
+		return 0
+	case strings.HasPrefix(s.Code, key), strings.HasPrefix(pinyin, key), strings.HasPrefix(s.Name, key):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// RankSearch sorts the matches of key by searchRank, keeping the order of equal ones.
+func RankSearch(key string, stocks []*Stock) {
+	sort.SliceStable(stocks, func(i, j int) bool {
+		return searchRank(key, stocks[i]) < searchRank(key, stocks[j])
+	})
+}
+
+// FilterSearch keeps the stocks of the option types and cuts the option page from them.
+func FilterSearch(stocks []*Stock, opts SearchOptions) []*Stock {
+	opts = opts.normalize()
+	filtered := make([]*Stock, 0, len(stocks))
+	for _, s := range stocks {
+		if opts.accepts(s.SecurityType) {
+			filtered = append(filtered, s)
+		}
+	}
+	start := (opts.Page - 1) * opts.PageSize
+	if start >= len(filtered) {
+		return make([]*Stock, 0)
+	}
+	end := start + opts.PageSize
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+	return filtered[start:end]
+}
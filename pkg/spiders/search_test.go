@@ -0,0 +1,53 @@
+package spiders_test
+
+import (
+	"stock/pkg/spiders"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEastMoneyProvider_SearchTypes(t *testing.T) {
+	spider := &spiders.EastMoneyProvider{}
+	data, err := spider.Search("300", spiders.SearchOptions{Types: []spiders.SecurityType{spiders.SecurityIndex}})
+	if assert.NoError(t, err) {
+		for _, s := range data {
+			assert.Equal(t, spiders.SecurityIndex, s.SecurityType)
+		}
+	}
+}
+
+func TestEastMoneyStockSearch_ToStocks(t *testing.T) {
+	ed := new(spiders.EastMoneyStockSearch)
+	data := `{"data":[{"Name":"东方财富","Code":"300059","PinYin":"DFCF","MktNum":"0","SecurityTypeName":"深A"},` +
+		`{"Name":"沪深300","Code":"000300","PinYin":"HS300","MktNum":"1","SecurityTypeName":"指数"},` +
+		`{"Name":"腾讯控股","Code":"00700","PinYin":"TXKG","MktNum":"116","SecurityTypeName":"港股"},` +
+		`{"Name":"沪深300ETF","Code":"510300","PinYin":"HS300ETF","MktNum":"1","SecurityTypeName":"基金"},` +
+		`{"Name":"东财转3","Code":"123041","PinYin":"DCZ3","MktNum":"0","SecurityTypeName":"债券"}]}`
+	if !assert.NoError(t, json.Unmarshal([]byte(data), ed)) {
+		return
+	}
+	stocks := ed.ToStocks()
+	types := make([]spiders.SecurityType, len(stocks))
+	for i, s := range stocks {
+		types[i] = s.SecurityType
+	}
+	assert.Equal(t, []spiders.SecurityType{
+		spiders.SecurityAShare, spiders.SecurityIndex, spiders.SecurityHK, spiders.SecurityFund, spiders.SecurityBond,
+	}, types)
+	assert.Equal(t, "DFCF", stocks[0].PinYin)
+
+	spiders.RankSearch("hs300", stocks)
+	assert.Equal(t, "000300", stocks[0].Code)
+	assert.Equal(t, "510300", stocks[1].Code)
+
+	page := spiders.FilterSearch(stocks, spiders.SearchOptions{
+		Page:     2,
+		PageSize: 1,
+		Types:    []spiders.SecurityType{spiders.SecurityIndex, spiders.SecurityFund},
+	})
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, "510300", page[0].Code)
+	}
+	assert.Empty(t, spiders.FilterSearch(stocks, spiders.SearchOptions{Page: 9}))
+}